@@ -0,0 +1,89 @@
+package struc2frm
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ariaAttrs links an input to its error message, message, suffix and title - with leading space;
+// element ids are the json name plus _err, _msg, _sfx and _title
+func (s2f *s2FT) ariaAttrs(fs *fieldSchema) string {
+	return s2f.ariaAttrsOf(fs, fs.inpName, fs.inpName)
+}
+
+// ariaAttrsOf is ariaAttrs() for inputs named other than by json name;
+// i.e. items[0].qty with id items_0_qty in repeatable groups
+func (s2f *s2FT) ariaAttrsOf(fs *fieldSchema, inpName, id string) string {
+	ret := ""
+	ids := []string{}
+	if _, hasError := s2f.errors[inpName]; hasError {
+		ret += " aria-invalid='true'"
+		ids = append(ids, id+"_err")
+	}
+	if _, hasMsg := s2f.messages[inpName]; hasMsg {
+		ids = append(ids, id+"_msg")
+	}
+	if fs.tag("suffix") != "" {
+		ids = append(ids, id+"_sfx")
+	}
+	if fs.tag("title") != "" {
+		ids = append(ids, id+"_title")
+	}
+	if len(ids) > 0 {
+		ret += fmt.Sprintf(" aria-describedby='%v'", strings.Join(ids, " "))
+	}
+	if _, ok := fs.parsed.get("required"); ok && fs.inputType != "checkboxgroup" && fs.inputType != "repeat" {
+		ret += " aria-required='true'" // not allowed on role group
+	}
+	return ret
+}
+
+// renderErrorSummary lists the field errors on top of the form -
+// linking to the inputs; in field order
+func (s2f *s2FT) renderErrorSummary(w io.Writer, v reflect.Value, sch *formSchema) {
+
+	type entry struct{ id, label, msg string }
+	entries := []entry{}
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip || sch.hidden(v, fs) {
+			continue
+		}
+		if msg, ok := s2f.errors[fs.inpName]; ok {
+			entries = append(entries, entry{fs.inpName, fs.label, msg})
+		}
+		if fs.inputType != "repeat" {
+			continue
+		}
+		rows := v.Field(fs.index)
+		for i := 0; i < rows.Len(); i++ {
+			for _, sub := range rowFields(fs) {
+				idx := strconv.Itoa(i)
+				if msg, ok := s2f.errors[rowInpName(fs.inpName, idx, sub.inpName)]; ok {
+					label := fmt.Sprintf("%v %v - %v", fs.label, i+1, sub.label)
+					entries = append(entries, entry{rowID(fs.inpName, idx, sub.inpName), label, msg})
+				}
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\t<div class='error-summary' role='alert' aria-labelledby='%v_errsum' >\n", template.HTMLEscapeString(s2f.Name))
+	if len(entries) == 1 {
+		fmt.Fprintf(w, "\t\t<p id='%v_errsum' >There is 1 error</p>\n", template.HTMLEscapeString(s2f.Name))
+	} else {
+		fmt.Fprintf(w, "\t\t<p id='%v_errsum' >There are %v errors</p>\n", template.HTMLEscapeString(s2f.Name), len(entries))
+	}
+	fmt.Fprint(w, "\t\t<ul>\n")
+	for _, e := range entries {
+		fmt.Fprintf(w, "\t\t\t<li><a href='#%v' >%v: %v</a></li>\n", e.id, e.label, e.msg)
+	}
+	fmt.Fprint(w, "\t\t</ul>\n")
+	fmt.Fprint(w, "\t</div>\n")
+}
@@ -0,0 +1,159 @@
+package struc2frm
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/a11y")
+
+type a11yFormT struct {
+	Name     string   `json:"name"      form:"required,maxlength='40',title='as in your passport'"`
+	Age      int      `json:"age"       form:"min=0,max=130,suffix='years'"`
+	Contact  string   `json:"contact"   form:"subtype='radiogroup',required"`
+	Topics   []string `json:"topics"    form:"subtype='checkboxgroup',min-count='1'"`
+	Comment  string   `json:"comment"   form:"subtype='textarea',title='optional'"`
+	Sep01    string   `json:"sep01"     form:"subtype='separator'"`
+	Accepted bool     `json:"accepted"  form:"label='I accept',required"`
+}
+
+func a11yConverter(errs map[string]string) *s2FT {
+	s2f := New()
+	s2f.CSS = ""
+	s2f.InstanceID = "a11y"
+	s2f.FocusFirstError = false
+	s2f.SetOptions("contact", []string{"email", "phone", ""}, []string{"Email", "Phone", ""})
+	s2f.SetOptions("topics", []string{"news", "offers"}, []string{"News", "Offers"})
+	s2f.AddErrors(errs)
+	return s2f
+}
+
+var tokenValue = regexp.MustCompile(`name='token'    type='hidden'   value='[0-9a-f]+'`)
+
+// TestA11yGolden compares the rendered forms with testdata/a11y/*.html;
+// run with -update after intended changes
+func TestA11yGolden(t *testing.T) {
+
+	tests := []struct {
+		name string
+		frm  a11yFormT
+		errs map[string]string
+	}{
+		{
+			name: "no-errors",
+			frm:  a11yFormT{Name: "Jane", Age: 42, Contact: "email"},
+		},
+		{
+			name: "errors",
+			frm:  a11yFormT{Age: 200},
+			errs: map[string]string{
+				"name":     "Name is required",
+				"age":      "Must be 130 or below",
+				"contact":  "Please choose",
+				"topics":   "Please choose at least one option",
+				"accepted": "Please accept",
+				"global":   "Please correct the errors below",
+			},
+		},
+	}
+
+	for idx, tt := range tests {
+
+		got := string(a11yConverter(tt.errs).Form(tt.frm))
+		got = tokenValue.ReplaceAllString(got, "name='token'    type='hidden'   value='TOKEN'")
+
+		for _, problem := range a11yProblems(got) {
+			t.Errorf("idx%2v: %v: %v", idx, tt.name, problem)
+		}
+
+		golden := filepath.Join("testdata", "a11y", tt.name+".html")
+		if *updateGolden {
+			if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("idx%2v: %v - run go test -run A11y -update", idx, err)
+		}
+		if got != string(want) {
+			t.Errorf("idx%2v: %v differs from %v\n%v", idx, tt.name, golden, got)
+		}
+	}
+}
+
+var (
+	idAttr       = regexp.MustCompile(`\sid='([^']+)'`)
+	forAttr      = regexp.MustCompile(`<label for='([^']+)'`)
+	describedBy  = regexp.MustCompile(`aria-describedby='([^']+)'`)
+	summaryLinks = regexp.MustCompile(`<a href='#([^']+)'`)
+	inputTag     = regexp.MustCompile(`<(input|select|textarea)\s[^>]*>`)
+)
+
+// a11yProblems checks the references between labels, inputs,
+// error messages, suffixes and the error summary
+func a11yProblems(html string) []string {
+
+	ids := map[string]int{}
+	for _, m := range idAttr.FindAllStringSubmatch(html, -1) {
+		ids[m[1]]++
+	}
+
+	problems := []string{}
+	for id, cnt := range ids {
+		if cnt > 1 {
+			problems = append(problems, "duplicate id "+id)
+		}
+	}
+	for _, m := range forAttr.FindAllStringSubmatch(html, -1) {
+		if ids[m[1]] == 0 {
+			problems = append(problems, "label for missing id "+m[1])
+		}
+	}
+	for _, m := range describedBy.FindAllStringSubmatch(html, -1) {
+		for _, id := range strings.Fields(m[1]) {
+			if ids[id] == 0 {
+				problems = append(problems, "aria-describedby refers to missing id "+id)
+			}
+		}
+	}
+	for _, m := range summaryLinks.FindAllStringSubmatch(html, -1) {
+		if ids[m[1]] == 0 {
+			problems = append(problems, "error summary links to missing id "+m[1])
+		}
+		if !regexp.MustCompile(`id='` + m[1] + `'[^>]*aria-invalid='true'`).MatchString(html) {
+			problems = append(problems, "field with error lacks aria-invalid: "+m[1])
+		}
+	}
+	for _, tag := range inputTag.FindAllString(html, -1) {
+		if strings.Contains(tag, "type='hidden'") || strings.Contains(tag, "type='radio'") ||
+			strings.Contains(tag, "type='checkbox' name='topics'") {
+			continue // hidden or labelled per option
+		}
+		m := idAttr.FindStringSubmatch(tag)
+		if m == nil {
+			problems = append(problems, "input without id: "+tag)
+			continue
+		}
+		if !strings.Contains(html, "<label for='"+m[1]+"'") {
+			problems = append(problems, "input without label: "+m[1])
+		}
+	}
+	return problems
+}
+
+func TestA11yEntryForm(t *testing.T) {
+	s2f := New()
+	s2f.SetOptions("department", []string{"ub", "fm"}, []string{"UB", "FM"})
+	s2f.SetOptions("fruit", []string{"pear", "plum"}, []string{"Pear", "Plum"})
+	errs, _ := entryForm{}.Validate()
+	s2f.AddErrors(errs)
+	for _, problem := range a11yProblems(string(s2f.Form(entryForm{}))) {
+		t.Error(problem)
+	}
+}
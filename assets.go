@@ -0,0 +1,129 @@
+package struc2frm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"time"
+)
+
+//go:embed default.css tpl-main.html struc2frm.js
+var embeddedFS embed.FS
+
+// OverrideFS takes precedence over the embedded assets
+// default.css, tpl-main.html and struc2frm.js;
+// files are re-read on every use - for hot reload during development, i.e.
+//
+//	struc2frm.OverrideFS = os.DirFS("/path/to/struc2frm")
+//
+// Files missing in OverrideFS are taken from the embedded assets.
+var OverrideFS fs.FS
+
+// readAsset reads a file from OverrideFS or the embedded assets
+func readAsset(name string) []byte {
+	if OverrideFS != nil {
+		bts, err := fs.ReadFile(OverrideFS, name)
+		if err == nil {
+			return bts
+		}
+	}
+	bts, err := fs.ReadFile(embeddedFS, name)
+	if err != nil {
+		pkgLogger().Error("asset is not embedded", "asset", name, "err", err) // a build defect - not a runtime condition
+	}
+	return bts
+}
+
+// defaultHTML is the page template of the example handlers
+func defaultHTML() string {
+	return string(readAsset("tpl-main.html"))
+}
+
+// defaultCSS is the default for s2f.CSS; <style> embedding added while rendering
+func defaultCSS() string {
+	return string(readAsset("default.css"))
+}
+
+// asset is a file served by AssetsHandler()
+type asset struct {
+	file        string // in embeddedFS or OverrideFS
+	contentType string
+	content     []byte
+	version     string // content hash; changes with every release touching the file
+}
+
+func (a asset) load() asset {
+	a.content = readAsset(a.file)
+	hash := sha256.Sum256(a.content)
+	a.version = hex.EncodeToString(hash[:])[:12]
+	return a
+}
+
+// assets by URL name; loaded once - unless OverrideFS is set
+var assets = map[string]asset{
+	"struc2frm.js":  asset{file: "struc2frm.js", contentType: "text/javascript; charset=utf-8"}.load(),
+	"struc2frm.css": asset{file: "default.css", contentType: "text/css; charset=utf-8"}.load(),
+}
+
+func lookupAsset(name string) (asset, bool) {
+	a, ok := assets[name]
+	if ok && OverrideFS != nil {
+		a = a.load()
+	}
+	return a, ok
+}
+
+// AssetsHandler serves struc2frm.js and struc2frm.css;
+// mount it under s2f.AssetsURL, i.e.
+//
+//	mux.Handle("/struc2frm-assets/", struc2frm.AssetsHandler())
+//
+// Requests carrying the current version ?v=... are cacheable forever;
+// others are revalidated by ETag.
+func AssetsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, ok := lookupAsset(path.Base(r.URL.Path))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", a.contentType)
+		w.Header().Set("ETag", fmt.Sprintf("%q", a.version))
+		if r.URL.Query().Get("v") == a.version {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		// handles If-None-Match against ETag
+		http.ServeContent(w, r, path.Base(r.URL.Path), time.Time{}, bytes.NewReader(a.content))
+	})
+}
+
+// assetURL returns the versioned URL of an asset under s2f.AssetsURL
+func (s2f *s2FT) assetURL(name string) string {
+	a, _ := lookupAsset(name)
+	return fmt.Sprintf("%v%v?v=%v", s2f.AssetsURL, name, a.version)
+}
+
+// useDataAttrs is true, if inline event handlers are replaced by data- attributes;
+// struc2frm.js wires them up
+func (s2f *s2FT) useDataAttrs() bool {
+	return s2f.CSPNonce != "" || s2f.AssetsURL != ""
+}
+
+// renderScript writes struc2frm.js - as reference to AssetsHandler() or inline
+func (s2f *s2FT) renderScript(w io.Writer) {
+	if s2f.AssetsURL != "" {
+		fmt.Fprintf(w, "\n<script type=\"text/javascript\" src='%v'%v></script>\n", s2f.assetURL("struc2frm.js"), s2f.nonceAttr())
+		return
+	}
+	fmt.Fprintf(w, "\n<script type=\"text/javascript\"%v>\n", s2f.nonceAttr())
+	w.Write(readAsset("struc2frm.js"))
+	fmt.Fprint(w, "</script>\n")
+}
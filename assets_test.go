@@ -0,0 +1,104 @@
+package struc2frm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetsHandler(t *testing.T) {
+
+	for name, a := range assets {
+
+		req, _ := http.NewRequest("GET", "/struc2frm-assets/"+name+"?v="+a.version, nil)
+		w := httptest.NewRecorder()
+		AssetsHandler().ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Body.Len() != len(a.content) {
+			t.Errorf("%v: got status %v with %v bytes", name, w.Code, w.Body.Len())
+		}
+		if !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+			t.Errorf("%v: versioned request should be cacheable - got %v", name, w.Header().Get("Cache-Control"))
+		}
+
+		req, _ = http.NewRequest("GET", "/struc2frm-assets/"+name, nil)
+		req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+		w = httptest.NewRecorder()
+		AssetsHandler().ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("%v: got status %v for matching ETag", name, w.Code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/struc2frm-assets/unknown.js", nil)
+	w := httptest.NewRecorder()
+	AssetsHandler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %v for unknown asset", w.Code)
+	}
+}
+
+func TestAssetsURL(t *testing.T) {
+
+	s2f := New()
+	s2f.AssetsURL = "/struc2frm-assets/"
+	s2f.SetOptions("department", []string{"ub", "fm"}, []string{"UB", "FM"})
+	s2f.SetOptions("items", []string{"anton", "berta"}, []string{"Anton", "Berta"})
+
+	got := string(s2f.Form(cspFormT{}))
+
+	for _, want := range []string{
+		"<link rel='stylesheet' href='/struc2frm-assets/struc2frm.css?v=" + assets["struc2frm.css"].version + "' />",
+		"src='/struc2frm-assets/struc2frm.js?v=" + assets["struc2frm.js"].version + "'",
+		"data-s2f-submit='true'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %v", want)
+		}
+	}
+	if strings.Contains(got, "function selectOptions") || strings.Contains(got, "div.struc2frm {") {
+		t.Errorf("output contains inline copies of JS or CSS")
+	}
+	if strings.Count(got, "struc2frm.js?v=") != 1 {
+		t.Errorf("script should be referenced once per form")
+	}
+}
+
+func TestAssetsURLCustomCSS(t *testing.T) {
+
+	s2f := New()
+	s2f.AssetsURL = "/struc2frm-assets/"
+	s2f.CSPNonce = "abc"
+	s2f.CSS = "div.struc2frm { color: teal; }"
+
+	got := string(s2f.Card(cspFormT{}))
+	if strings.Contains(got, "struc2frm.css?v=") {
+		t.Errorf("custom CSS must not be replaced by the default stylesheet")
+	}
+	if !strings.Contains(got, "<style nonce='abc'>\ndiv.struc2frm { color: teal; }") {
+		t.Errorf("custom CSS should be inlined with nonce\n%v", got)
+	}
+}
+
+func TestOverrideFS(t *testing.T) {
+
+	OverrideFS = fstest.MapFS{
+		"default.css": &fstest.MapFile{Data: []byte("div.struc2frm { color: red; }")},
+	}
+	defer func() { OverrideFS = nil }()
+
+	if got := New().CSS; got != "div.struc2frm { color: red; }" {
+		t.Errorf("CSS should be taken from OverrideFS - got %v", got)
+	}
+	if !strings.Contains(defaultHTML(), "<!DOCTYPE html>") {
+		t.Errorf("files missing in OverrideFS should be taken from the embedded assets")
+	}
+
+	req, _ := http.NewRequest("GET", "/struc2frm-assets/struc2frm.css", nil)
+	w := httptest.NewRecorder()
+	AssetsHandler().ServeHTTP(w, req)
+	if w.Body.String() != "div.struc2frm { color: red; }" {
+		t.Errorf("AssetsHandler() should serve from OverrideFS - got %v", w.Body.String())
+	}
+}
@@ -0,0 +1,81 @@
+package struc2frm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// honeypotName is the name of the hidden input only bots fill in;
+// named like an input bots love to fill
+const honeypotName = "homepage_s2f"
+
+// renderedName is the name of the hidden input carrying the signed render time
+const renderedName = "rendered_s2f"
+
+// renderBotGuard writes the honeypot input and the signed render timestamp;
+// both only if enabled by s2f.Honeypot or s2f.MinFillTime
+func (s2f *s2FT) renderBotGuard(w io.Writer) {
+	if s2f.MinFillTime > 0 {
+		fmt.Fprintf(w, "\t<input name='%v' type='hidden'   value='%v' />\n", renderedName, s2f.renderedValue(time.Now()))
+	}
+	if s2f.Honeypot {
+		// concealed by CSS - not by type='hidden', which bots skip
+		fmt.Fprintf(w, "\t<div class='s2f-hp' aria-hidden='true'>\n")
+		fmt.Fprintf(w, "\t\t<label for='%v' >Leave empty</label>\n", honeypotName)
+		fmt.Fprintf(w, "\t\t<input type='text' name='%v' id='%v' value='' tabindex='-1' autocomplete='off' />\n", honeypotName, honeypotName)
+		fmt.Fprintf(w, "\t</div>\n")
+	}
+}
+
+// renderedValue signs the render time with s2f.Salt;
+// i.e. 1600000000-8f3a...
+func (s2f *s2FT) renderedValue(t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return ts + "-" + s2f.sign(ts)
+}
+
+func (s2f *s2FT) sign(s string) string {
+	mac := hmac.New(sha256.New, []byte(s2f.Salt))
+	io.WriteString(mac, s)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkBotGuard rejects submissions with filled honeypot
+// or submissions arriving faster than s2f.MinFillTime after rendering.
+// Render times older than s2f.MaxFillTime yield ErrTokenExpired -
+// a captured render time can not be replayed forever.
+// A filled honeypot is always rejected - even if s2f.Honeypot is off.
+func (s2f *s2FT) checkBotGuard(r *http.Request) error {
+
+	if r.Form.Get(honeypotName) != "" {
+		return ErrHoneypotFilled
+	}
+
+	if s2f.MinFillTime <= 0 {
+		return nil
+	}
+
+	parts := strings.SplitN(r.Form.Get(renderedName), "-", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(s2f.sign(parts[0]))) {
+		return ErrTokenInvalid // missing or forged render time
+	}
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age < s2f.MinFillTime {
+		return ErrSubmittedTooFast
+	}
+	if s2f.MaxFillTime > 0 && age > s2f.MaxFillTime {
+		return ErrTokenExpired
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package struc2frm
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBotGuard(t *testing.T) {
+
+	s2f := New()
+	s2f.Honeypot = true
+	s2f.MinFillTime = 3 * time.Second
+
+	html := string(s2f.Form(entryForm{}))
+	for _, want := range []string{
+		"name='" + renderedName + "'",
+		"<input type='text' name='" + honeypotName + "'",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("rendered form lacks %v", want)
+		}
+	}
+
+	tests := []struct {
+		rendered time.Time
+		honeypot string
+		forged   bool
+		want     error
+	}{
+		{
+			rendered: time.Now().Add(-10 * time.Second),
+			want:     nil,
+		},
+		{
+			rendered: time.Now(),
+			want:     ErrSubmittedTooFast,
+		},
+		{
+			rendered: time.Now().Add(-10 * time.Second),
+			honeypot: "http://spam.example.com",
+			want:     ErrHoneypotFilled,
+		},
+		{
+			rendered: time.Now().Add(-10 * time.Second),
+			forged:   true,
+			want:     ErrTokenInvalid,
+		},
+		{
+			rendered: time.Now().Add(-25 * time.Hour), // replayed - older than MaxFillTime
+			want:     ErrTokenExpired,
+		},
+	}
+	for idx, tt := range tests {
+		data := url.Values{}
+		data.Set("token", s2f.FormToken())
+		data.Set("groups", "3")
+		data.Set(honeypotName, tt.honeypot)
+		data.Set(renderedName, s2f.renderedValue(tt.rendered))
+		if tt.forged {
+			data.Set(renderedName, s2f.renderedValue(tt.rendered)+"0")
+		}
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		frm := entryForm{}
+		populated, err := s2f.Decode(req, &frm)
+		if !populated || !errors.Is(err, tt.want) {
+			t.Errorf("idx%2v: got %v %v - want %v", idx, populated, err, tt.want)
+		}
+	}
+}
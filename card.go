@@ -2,61 +2,73 @@ package struc2frm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"io"
 	"reflect"
 	"strings"
 )
 
 // Card creates an HTML list view - instead of an HTML form;
-// TODO: render fieldsets
+// errors are rendered as text - use RenderCard() to handle them.
 func (s2f *s2FT) Card(intf interface{}) template.HTML {
+	w := &bytes.Buffer{}
+	if err := s2f.RenderCard(w, intf); err != nil {
+		return template.HTML(template.HTMLEscapeString(fmt.Sprintf("struct2form.Card() - %v", err)))
+	}
+	return template.HTML(w.String())
+}
+
+// RenderCard writes the HTML list view for struct instance intf to wr;
+// errors as for RenderForm().
+func (s2f *s2FT) RenderCard(wr io.Writer, intf interface{}) error {
+	return s2f.RenderCardContext(context.Background(), wr, intf)
+}
+
+// RenderCardContext is RenderCard() - with ctx passed to options providers.
+// TODO: render fieldsets
+func (s2f *s2FT) RenderCardContext(ctx context.Context, wr io.Writer, intf interface{}) error {
 
 	v := reflect.ValueOf(intf) // ifVal
-	typeOfS := v.Type()
 	// v = v.Elem()            // dereference
 
-	if v.Kind().String() != "struct" {
-		return template.HTML(fmt.Sprintf("struct2form.Card() - arg1 must be struct - is %v", v.Kind()))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w - is %v", ErrNotStruct, v.Kind())
+	}
+
+	sch := schemaOf(v.Type())
+	if err := sch.tagErr(); err != nil {
+		return err
+	}
+	resolved, err := s2f.resolveOptions(ctx, v, sch)
+	if err != nil {
+		return err
 	}
 
 	labels := make([]string, 0, v.NumField())
 	values := make([]string, 0, v.NumField())
 	sfxs := make([]string, 0, v.NumField())
+	inpNames := make([]string, 0, v.NumField()) // for AddMessage()
 	statusMsg := ""
 
-	for i := 0; i < v.NumField(); i++ {
+	for fIdx := range sch.fields {
 
-		// struct field name; i.e. Name, Birthdate
-		fn := typeOfS.Field(i).Name
-		if fn[0:1] != strings.ToUpper(fn[0:1]) { // only used to find unexported fields; otherwise json tag name is used
+		fs := &sch.fields[fIdx]
+		fn := fs.name     // struct field name; i.e. Name, Birthdate
+		if !fs.exported { // only used to find unexported fields; otherwise json tag name is used
 			continue // skip unexported
 		}
 
-		inpName := typeOfS.Field(i).Tag.Get("json") // i.e. date_layout
-		inpName = strings.Replace(inpName, ",omitempty", "", -1)
-		inpLabel := labelize(inpName)
-
-		attrs := typeOfS.Field(i).Tag.Get("form") // i.e. form:"maxlength='42',size='28',suffix='optional'"
-
-		if structTag(attrs, "label") != "" {
-			inpLabel = structTag(attrs, "label")
-		}
-
-		if strings.Contains(attrs, ", ") || strings.Contains(attrs, ", ") {
-			return template.HTML(fmt.Sprintf("struct2form.Card() - field %v: tag 'form' cannot contain ', ' or ' ,' ", inpName))
-		}
+		inpName := fs.inpName // i.e. date_layout
+		inpLabel := fs.label
 
-		if commaInsideQuotes(attrs) {
-			return template.HTML(fmt.Sprintf("struct2form.Card() - field %v: tag 'form' - use &comma; instead of ',' inside of single quotes values", inpName))
-		}
-
-		if attrs == "-" {
+		if fs.skip || sch.hidden(v, fs) {
 			continue
 		}
 
-		if fn == "Status" || fn == "Msg" {
-			val := v.Field(i).Interface()
+		if fs.tag("role") == "status" { // server message - not a card entry
+			val := v.Field(fs.index).Interface()
 			if valStr, ok := val.(string); ok {
 				if statusMsg != "" {
 					statusMsg += " - "
@@ -66,7 +78,18 @@ func (s2f *s2FT) Card(intf interface{}) template.HTML {
 			continue
 		}
 
-		val := v.Field(i).Interface()
+		if fs.inputType == "repeat" {
+			if v.Field(fs.index).Len() == 0 && s2f.SkipEmpty {
+				continue
+			}
+			labels = append(labels, inpLabel)
+			values = append(values, rowsCard(v.Field(fs.index), fs, resolved))
+			sfxs = append(sfxs, fs.tag("suffix"))
+			inpNames = append(inpNames, inpName)
+			continue
+		}
+
+		val := v.Field(fs.index).Interface()
 
 		if fmt.Sprint(val) == "" && s2f.SkipEmpty {
 			if !strings.HasPrefix(fn, "Separator") { // separators should be rendered, though they have no value
@@ -84,22 +107,23 @@ func (s2f *s2FT) Card(intf interface{}) template.HTML {
 		// Replace <select...> keys with values
 		idx := len(values) - 1
 		if values[idx] != "" {
-			if opts, ok := s2f.selectOptions[inpName]; ok {
+			if opts, ok := resolved[inpName]; ok {
 				for _, opt := range opts {
 					// log.Printf("For %12v: Comparing %5v to %5v  %5v", inpName, values[idx], opt.Key, opt.Val)
 					if values[idx] == opt.Key && opt.Val != "" {
-						values[idx] = opt.Val
+						values[idx] = opt.Label() // with group
 					}
 				}
 			}
 		}
 
-		sfx := structTag(attrs, "suffix")
+		sfx := fs.tag("suffix")
 		sfxs = append(sfxs, sfx)
+		inpNames = append(inpNames, inpName)
 
 	}
 
-	w := &bytes.Buffer{}
+	w := &errWriter{w: wr}
 
 	s2f.RenderCSS(w)
 
@@ -107,18 +131,16 @@ func (s2f *s2FT) Card(intf interface{}) template.HTML {
 	fmt.Fprintf(w, "<div class='struc2frm struc2frm-%v'>\n", s2f.InstanceID)
 
 	if s2f.ShowHeadline {
-		fmt.Fprintf(w, "<h3>%v</h3>\n", labelize(typeOfS.Name()))
+		fmt.Fprintf(w, "<h3>%v</h3>\n", labelize(sch.name))
 	}
 
+	s2f.renderGlobalMessage(w)
+
 	fmt.Fprintf(w, "<ul>\n")
 
 	// fieldsetOpen := false
 
-	valid := true // default
-	errs := map[string]string{}
-	if vldr, ok := intf.(Validator); ok { // if validator interface is implemented...
-		errs, valid = vldr.Validate() // ...check for validity
-	}
+	errs, valid := s2f.Validate(intf) // if validator interface is implemented; ignoring hidden fields
 	if valid {
 		for idx, label := range labels {
 			if strings.HasPrefix(label, "Separator") {
@@ -143,6 +165,10 @@ func (s2f *s2FT) Card(intf interface{}) template.HTML {
 				}
 			}
 
+			if m, ok := s2f.messages[inpNames[idx]]; ok {
+				fmt.Fprintf(w, "<span class='%v-block' >%v</span>", m.level, m.text)
+			}
+
 			fmt.Fprintf(w, "\t</li>\n")
 		}
 	} else {
@@ -158,8 +184,25 @@ func (s2f *s2FT) Card(intf interface{}) template.HTML {
 
 	fmt.Fprint(w, "</div><!-- </div class='struc2frm'... -->\n")
 
-	// global replacements
-	ret := strings.ReplaceAll(w.String(), "&comma;", ",")
+	return w.err
+}
 
-	return template.HTML(ret)
+// rowsCard renders the rows of a repeatable group - one line per row;
+// i.e. Product: Widget, Qty: 3
+func rowsCard(rows reflect.Value, fs *fieldSchema, resolved map[string]options) string {
+	lines := make([]string, 0, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		cells := []string{}
+		for _, sub := range rowFields(fs) {
+			valStr := ValToString(rows.Index(i).Field(sub.index))
+			for _, opt := range resolved[rowOptionsName(fs.inpName, sub.inpName)] {
+				if valStr == opt.Key && opt.Val != "" {
+					valStr = opt.Label()
+				}
+			}
+			cells = append(cells, fmt.Sprintf("%v: %v", sub.label, template.HTMLEscapeString(valStr)))
+		}
+		lines = append(lines, strings.Join(cells, ", "))
+	}
+	return strings.Join(lines, "<br>\n")
 }
@@ -0,0 +1,178 @@
+package struc2frm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// knownTagKeys are the keys of the 'form' struct tag
+// recognized by Form() and Card()
+var knownTagKeys = map[string]bool{
+	"accept": true, "accesskey": true, "autocapitalize": true, "autofocus": true, "blank-rows": true,
+	"cols": true, "dependson": true, "filename": true, "hideif": true, "inputmode": true, "label": true, "label-style": true,
+	"max": true, "max-count": true, "maxlength": true, "maxsize": true, "min": true, "min-count": true, "multiple": true,
+	"nobreak": true, "onchange": true, "pattern": true, "placeholder": true,
+	"required": true, "role": true, "rows": true, "showif": true, "size": true, "step": true, "subtype": true,
+	"suffix": true, "title": true, "wildcardselect": true,
+}
+
+// subtypesByType lists the valid subtype values by golang type
+var subtypesByType = map[string][]string{
+	"string":    {"separator", "fieldset", "date", "time", "textarea", "select", "radiogroup"},
+	"[]string":  {"select", "checkboxgroup"},
+	"int":       {"select"},
+	"float64":   {"select"},
+	"[]int":     {"select", "checkboxgroup"},
+	"[]float64": {"select", "checkboxgroup"},
+	"bool":      {"select"},
+	"[]bool":    {"select"},
+}
+
+// FieldError is a problem with the struct tags of a field
+type FieldError struct {
+	Field string // struct field name; i.e. DateLayout
+	Err   error
+}
+
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("field %v: %v", fe.Field, fe.Err)
+}
+
+// Unwrap gives access to TagError
+func (fe *FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// CheckField validates the struct tags of one exported struct field;
+// shared by s2f.Check() and the analyzer in package struc2frm/vet.
+// tp is the golang type name; i.e. string, []string, []uint8.
+// The problems are returned in the order of the rules.
+func CheckField(tp, jsonTag, formTag string) []error {
+
+	if formTag == "-" {
+		return nil
+	}
+
+	errs := []error{}
+
+	inpName := strings.Replace(jsonTag, ",omitempty", "", -1)
+	if inpName == "" || inpName == "-" {
+		errs = append(errs, fmt.Errorf("missing json tag - required as input name"))
+	}
+
+	ft, err := parseFormTag(formTag)
+	if err != nil {
+		return append(errs, err)
+	}
+
+	for _, pair := range ft {
+		if !knownTagKeys[strings.ToLower(pair.Key)] {
+			errs = append(errs, fmt.Errorf("tag 'form': unknown key '%v'", pair.Key))
+		}
+	}
+
+	if subtype, ok := ft.get("subtype"); ok {
+		valid := false
+		for _, st := range subtypesByType[tp] {
+			if st == subtype {
+				valid = true
+			}
+		}
+		if !valid {
+			errs = append(errs, fmt.Errorf("tag 'form': subtype='%v' is not supported for type %v", subtype, tp))
+		}
+	}
+
+	if _, ok := ft.get("multiple"); ok && !strings.HasPrefix(tp, "[]") {
+		errs = append(errs, fmt.Errorf("tag 'form': multiple requires a slice type - not %v", tp))
+	}
+
+	if _, ok := ft.get("multiple"); ok && tp == "[]uint8" {
+		errs = append(errs, fmt.Errorf("tag 'form': multiple requires []FileUpload for file inputs"))
+	}
+
+	if _, ok := ft.get("filename"); ok && tp != "[]uint8" {
+		errs = append(errs, fmt.Errorf("tag 'form': filename requires type []byte - not %v", tp))
+	}
+
+	if val, ok := ft.get("maxsize"); ok {
+		if _, err := parseSize(val); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, key := range []string{"min-count", "max-count"} {
+		if val, ok := ft.get(key); ok {
+			if _, err := strconv.Atoi(val); err != nil {
+				errs = append(errs, fmt.Errorf("tag 'form': %v='%v' must be an integer", key, val))
+			}
+		}
+	}
+
+	if role, ok := ft.get("role"); ok && role != "status" {
+		errs = append(errs, fmt.Errorf("tag 'form': role='%v' is not supported - only status", role))
+	}
+
+	if _, err := conditionOf(ft); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Check validates the struct tags of intf
+// before Form() or Card() render errors into the HTML;
+// additionally checks for options of select and radiogroup inputs,
+// for the fields referenced by showif, hideif, dependson and filename
+// and the row fields of repeatable groups.
+// Returns nil or joined *FieldError.
+func (s2f *s2FT) Check(intf interface{}) error {
+
+	v := reflect.ValueOf(intf)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("struc2frm: Check() - arg1 must be struct - is %v", v.Kind())
+	}
+
+	sch := schemaOf(v.Type())
+	sf := v.Type()
+	errs := []error{}
+
+	for _, fs := range sch.fields {
+		if !fs.exported {
+			continue
+		}
+		for _, err := range CheckField(fs.tp, sf.Field(fs.index).Tag.Get("json"), fs.attrs) {
+			errs = append(errs, &FieldError{Field: fs.name, Err: err})
+		}
+		if fs.skip || fs.tagErr != nil {
+			continue
+		}
+		if fs.cond != nil && sch.byInpName(fs.cond.field) == nil {
+			errs = append(errs, &FieldError{Field: fs.name, Err: fmt.Errorf("condition refers to unknown field '%v'", fs.cond.field)})
+		}
+		for _, parent := range fs.dependsOn {
+			if sch.byInpName(parent) == nil {
+				errs = append(errs, &FieldError{Field: fs.name, Err: fmt.Errorf("dependson refers to unknown field '%v'", parent)})
+			}
+		}
+		if name := fs.tag("filename"); name != "" && sch.filenameField(&fs) == nil {
+			errs = append(errs, &FieldError{Field: fs.name, Err: fmt.Errorf("filename refers to unknown string field '%v'", name)})
+		}
+		if fs.inputType == "repeat" {
+			errs = append(errs, s2f.checkRepeat(&fs)...)
+		}
+		if fs.inputType == "select" || fs.inputType == "radiogroup" || fs.inputType == "checkboxgroup" {
+			if !s2f.hasOptions(fs.inpName) {
+				errs = append(errs, &FieldError{Field: fs.name, Err: fmt.Errorf("no options for %v - use SetOptions(), SetOptionsSource() or SetOptionsProvider()", fs.inputType)})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package struc2frm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+
+	s2f := New()
+	err := s2f.Check(entryForm{})
+	if err == nil {
+		t.Fatalf("want errors for missing select options")
+	}
+	for _, want := range []string{"field Department: no options for select", "field Fruit: no options for radiogroup"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("want %v - got %v", want, err)
+		}
+	}
+
+	s2f.SetOptions("department", []string{"ub", "fm"}, []string{"UB", "FM"})
+	s2f.SetOptions("items2", []string{"anton", "berta"}, []string{"Anton", "Berta"})
+	s2f.SetOptions("fruit", []string{"pear", "plum"}, []string{"Pear", "Plum"})
+	if err := s2f.Check(&entryForm{}); err != nil {
+		t.Errorf("want no errors - got %v", err)
+	}
+
+	type badFormT struct {
+		Groups int `json:"groups" form:"subtype='textarea',label='it's'"`
+	}
+	err = s2f.Check(badFormT{})
+	var te *TagError
+	if !errors.As(err, &te) {
+		t.Errorf("want TagError - got %v", err)
+	}
+}
+
+func TestCheckField(t *testing.T) {
+
+	tests := []struct {
+		tp      string
+		formTag string
+		want    string // substring of the first error; empty for none
+	}{
+		{"[]string", "subtype='checkboxgroup',min-count='1',max-count='3'", ""},
+		{"[]int", "subtype='checkboxgroup',required", ""},
+		{"string", "subtype='checkboxgroup'", "subtype='checkboxgroup' is not supported for type string"},
+		{"[]string", "subtype='checkboxgroup',min-count='one'", "min-count='one' must be an integer"},
+		{"string", "role='status'", ""},
+		{"string", "role='alert'", "role='alert' is not supported - only status"},
+		{"[]FileUpload", "multiple='true',maxsize='2M',accept='.txt'", ""},
+		{"[]uint8", "multiple='true'", "multiple requires []FileUpload for file inputs"},
+		{"FileUpload", "maxsize='lots'", "maxsize='lots' must be bytes"},
+		{"string", "filename='upload_name'", "filename requires type []byte - not string"},
+	}
+	for idx, tt := range tests {
+		errs := CheckField(tt.tp, `name`, tt.formTag)
+		if tt.want == "" {
+			if len(errs) > 0 {
+				t.Errorf("idx%2v: want no errors - got %v", idx, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), tt.want) {
+			t.Errorf("idx%2v: want %v - got %v", idx, tt.want, errs)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package struc2frm
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// groupMarker is the hidden empty companion of a checkbox group;
+// posted even if no box is checked - see resetGroups()
+func groupMarker(inpName string) string {
+	return fmt.Sprintf("\t<input type='hidden' name='%v' value='' />\n", inpName)
+}
+
+// resetGroups sets the posted checkbox groups of *ptr2Struct to empty slices -
+// decoding would otherwise append the checked values to init values;
+// groups not posted at all - i.e. inside disabled fieldsets - keep their values;
+// returns form without the empty markers
+func resetGroups(form url.Values, ptr2Struct interface{}) url.Values {
+
+	v := reflect.ValueOf(ptr2Struct)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return form
+	}
+	v = v.Elem()
+
+	cleaned := maps.Clone(form)
+	sch := schemaOf(v.Type())
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip || fs.inputType != "checkboxgroup" {
+			continue
+		}
+		vals, posted := form[fs.inpName]
+		if !posted {
+			continue
+		}
+		fld := v.Field(fs.index)
+		fld.Set(reflect.MakeSlice(fld.Type(), 0, 0))
+		cleaned[fs.inpName] = slices.DeleteFunc(slices.Clone(vals), func(val string) bool { return val == "" })
+	}
+	return cleaned
+}
+
+// countError checks the 'form' tags required, min-count and max-count
+// of a checkbox group against the number of checked options;
+// returns the error message or ""
+func countError(v reflect.Value, fs *fieldSchema) string {
+
+	if fs.inputType != "checkboxgroup" {
+		return ""
+	}
+
+	minCnt, _ := strconv.Atoi(fs.tag("min-count"))
+	if _, ok := fs.parsed.get("required"); ok && minCnt < 1 {
+		minCnt = 1
+	}
+	maxCnt, _ := strconv.Atoi(fs.tag("max-count"))
+
+	cnt := v.Field(fs.index).Len()
+	switch {
+	case cnt < minCnt && minCnt == 1:
+		return "Please choose at least one option"
+	case cnt < minCnt:
+		return fmt.Sprintf("Please choose at least %v options", minCnt)
+	case maxCnt > 0 && cnt > maxCnt:
+		return fmt.Sprintf("Please choose at most %v options", maxCnt)
+	}
+	return ""
+}
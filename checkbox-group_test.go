@@ -0,0 +1,138 @@
+package struc2frm
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type toppingsFormT struct {
+	Toppings []string `json:"toppings"  form:"subtype='checkboxgroup',wildcardselect='true',min-count='1',max-count='2'"`
+	Sizes    []int    `json:"sizes"     form:"subtype='checkboxgroup',required"`
+}
+
+func TestCheckboxGroup(t *testing.T) {
+
+	s2f := New()
+	s2f.SetOptions("toppings", []string{"ham", "olives", "onions"}, []string{"Ham", "Olives", "Onions"})
+	s2f.SetOptions("sizes", []string{"26", "32"}, []string{"Medium", "Large"})
+
+	html := string(s2f.Form(toppingsFormT{Toppings: []string{"olives"}}))
+	for idx, want := range []string{
+		"<fieldset class='checkbox-group' id='toppings' >\n\t<legend class='group-legend' style='' >Toppings</legend>",
+		"<span class='checkbox-item'><input type='checkbox' name='toppings' id='toppings_0' value='ham' /><label for='toppings_0' >Ham</label></span>",
+		"<input type='checkbox' name='toppings' id='toppings_1' value='olives' checked /><label for='toppings_1' >Olives</label>",
+		"name='toppings_so' id='toppings_so'",
+		"function s2fSelectables",
+		"<input type='checkbox' name='sizes' id='sizes_1' value='32' />",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("idx%2v: missing %q", idx, want)
+		}
+	}
+
+	tests := []struct {
+		toppings []string
+		sizes    []string
+		wantErrs map[string]string
+	}{
+		{
+			toppings: []string{"ham"},
+			sizes:    []string{"32"},
+			wantErrs: map[string]string{},
+		},
+		{
+			toppings: []string{"ham", "olives", "onions"},
+			sizes:    []string{"26", "32"},
+			wantErrs: map[string]string{"toppings": "Please choose at most 2 options"},
+		},
+		{
+			toppings: nil,
+			sizes:    nil,
+			wantErrs: map[string]string{
+				"toppings": "Please choose at least one option",
+				"sizes":    "Please choose at least one option",
+			},
+		},
+	}
+	for idx, tt := range tests {
+
+		data := url.Values{}
+		data.Set("token", s2f.FormToken())
+		data["toppings"] = tt.toppings
+		data["sizes"] = tt.sizes
+		data.Set("btnSubmit", "1")
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		frm := toppingsFormT{}
+		populated, err := s2f.Decode(req, &frm)
+		if !populated || err != nil {
+			t.Errorf("idx%2v: decode %v %v", idx, populated, err)
+			continue
+		}
+		if len(frm.Toppings) != len(tt.toppings) || len(frm.Sizes) != len(tt.sizes) {
+			t.Errorf("idx%2v: decoded %v %v", idx, frm.Toppings, frm.Sizes)
+		}
+
+		errs, valid := s2f.Validate(frm)
+		if valid != (len(tt.wantErrs) == 0) || len(errs) != len(tt.wantErrs) {
+			t.Errorf("idx%2v: Validate() got %v %v", idx, errs, valid)
+		}
+		for key, msg := range tt.wantErrs {
+			if errs[key] != msg {
+				t.Errorf("idx%2v: %v got %q - want %q", idx, key, errs[key], msg)
+			}
+		}
+	}
+}
+
+func TestCheckboxGroupDeselect(t *testing.T) {
+
+	s2f := New()
+	s2f.SetOptions("toppings", []string{"ham", "olives", "onions"}, []string{"Ham", "Olives", "Onions"})
+	s2f.SetOptions("sizes", []string{"26", "32"}, []string{"Medium", "Large"})
+
+	html := string(s2f.Form(toppingsFormT{}))
+	for _, want := range []string{
+		"<input type='hidden' name='toppings' value='' />\n\t</fieldset>",
+		"<input type='hidden' name='sizes' value='' />\n\t</fieldset>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing group marker %q", want)
+		}
+	}
+
+	tests := []struct {
+		toppings     []string // as posted - with marker
+		sizes        []string
+		wantToppings []string
+		wantSizes    []int
+	}{
+		{[]string{"", "olives"}, []string{"", "32"}, []string{"olives"}, []int{32}}, // one deselected
+		{[]string{""}, []string{""}, []string{}, []int{}},                           // all deselected
+		{nil, nil, []string{"ham", "olives"}, []int{26, 32}},                        // not posted - i.e. disabled fieldset
+	}
+	for idx, tt := range tests {
+
+		data := url.Values{}
+		data.Set("token", s2f.FormToken())
+		if tt.toppings != nil {
+			data["toppings"] = tt.toppings
+			data["sizes"] = tt.sizes
+		}
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		frm := toppingsFormT{Toppings: []string{"ham", "olives"}, Sizes: []int{26, 32}} // init values
+		if _, err := s2f.Decode(req, &frm); err != nil {
+			t.Errorf("idx%2v: decode %v", idx, err)
+			continue
+		}
+		if !slices.Equal(frm.Toppings, tt.wantToppings) || !slices.Equal(frm.Sizes, tt.wantSizes) {
+			t.Errorf("idx%2v: got %v %v - want %v %v", idx, frm.Toppings, frm.Sizes, tt.wantToppings, tt.wantSizes)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package struc2frm
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"strings"
+)
+
+// condition of the 'form' tag keys showif and hideif;
+// i.e. showif='reason=other' or hideif='contact=none|unknown'
+type condition struct {
+	hide   bool     // hideif instead of showif
+	field  string   // json name of the controlling field
+	values []string // any of them matches
+}
+
+// parseCondition parses the value of showif or hideif
+func parseCondition(s string, hide bool) (*condition, error) {
+	field, vals, ok := strings.Cut(s, "=")
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return nil, fmt.Errorf("tag 'form': condition '%v' - expected field=value", s)
+	}
+	cond := &condition{hide: hide, field: field}
+	for _, val := range strings.Split(vals, "|") {
+		cond.values = append(cond.values, strings.TrimSpace(val))
+	}
+	return cond, nil
+}
+
+// conditionOf returns the showif or hideif condition of a 'form' tag;
+// nil, if there is none
+func conditionOf(ft formTag) (*condition, error) {
+	if s, ok := ft.get("showif"); ok {
+		return parseCondition(s, false)
+	}
+	if s, ok := ft.get("hideif"); ok {
+		return parseCondition(s, true)
+	}
+	return nil, nil
+}
+
+// attr renders the condition as data- attribute for struc2frm.js
+func (cond *condition) attr() string {
+	key := "data-s2f-showif"
+	if cond.hide {
+		key = "data-s2f-hideif"
+	}
+	return fmt.Sprintf("%v='%v'", key, template.HTMLEscapeString(cond.field+"="+strings.Join(cond.values, "|")))
+}
+
+// visible evaluates the condition against the submitted values of the controlling field
+func (cond *condition) visible(vals []string) bool {
+	match := false
+	for _, val := range vals {
+		for _, want := range cond.values {
+			if val == want {
+				match = true
+			}
+		}
+	}
+	return match != cond.hide
+}
+
+// fieldValues returns the values of field fs as strings;
+// one per element for slices
+func fieldValues(v reflect.Value, fs *fieldSchema) []string {
+	val := v.Field(fs.index)
+	if !fs.isSlice {
+		return []string{ValToString(val)}
+	}
+	vals := make([]string, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		vals = append(vals, ValToString(val.Index(i)))
+	}
+	return vals
+}
+
+// byInpName returns the field with json name inpName - or nil
+func (sch *formSchema) byInpName(inpName string) *fieldSchema {
+	for i := range sch.fields {
+		if sch.fields[i].inpName == inpName {
+			return &sch.fields[i]
+		}
+	}
+	return nil
+}
+
+// hidden is true, if the showif/hideif condition of fs is false for the values of struct v;
+// conditions on unknown fields never hide
+func (sch *formSchema) hidden(v reflect.Value, fs *fieldSchema) bool {
+	if fs.cond == nil {
+		return false
+	}
+	ctrl := sch.byInpName(fs.cond.field)
+	if ctrl == nil || !ctrl.exported {
+		return false
+	}
+	return !fs.cond.visible(fieldValues(v, ctrl))
+}
+
+// Validate checks the 'form' tags required, min-count and max-count of checkbox groups
+// and min and max of repeatable groups;
+// it calls Validate() of intf and of the rows of repeatable groups - if implemented;
+// errors for fields hidden by showif or hideif are dropped;
+// valid is recomputed, if errors were added or dropped.
+// Use it instead of calling frm.Validate() directly.
+func (s2f *s2FT) Validate(intf interface{}) (map[string]string, bool) {
+
+	errs, valid := map[string]string{}, true
+	if vldr, ok := intf.(Validator); ok {
+		errs, valid = vldr.Validate()
+		if errs == nil {
+			errs = map[string]string{}
+		}
+	}
+
+	v := reflect.ValueOf(intf)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return errs, valid
+	}
+
+	sch := schemaOf(v.Type())
+	changed := false
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip {
+			continue
+		}
+		if sch.hidden(v, fs) {
+			if _, ok := errs[fs.inpName]; ok {
+				delete(errs, fs.inpName)
+				changed = true
+			}
+			continue
+		}
+		if fs.inputType == "repeat" {
+			for key, msg := range rowErrors(v, fs) {
+				if _, ok := errs[key]; !ok {
+					errs[key] = msg
+					changed = true
+				}
+			}
+		}
+		if _, ok := errs[fs.inpName]; ok {
+			continue // message of the Validator takes precedence
+		}
+		msg := countError(v, fs)
+		if msg == "" {
+			msg = rowCountError(v, fs)
+		}
+		if msg != "" {
+			errs[fs.inpName] = msg
+			changed = true
+		}
+	}
+	if changed {
+		valid = len(errs) == 0
+	}
+	return errs, valid
+}
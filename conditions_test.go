@@ -0,0 +1,107 @@
+package struc2frm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type condFormT struct {
+	Reason      string `json:"reason"        form:"subtype='select'"`
+	OtherReason string `json:"other_reason"  form:"showif='reason=other',maxlength='40'"`
+	Newsletter  bool   `json:"newsletter"`
+	Email       string `json:"email"         form:"hideif='newsletter=false'"`
+}
+
+func (frm condFormT) Validate() (map[string]string, bool) {
+	errs := map[string]string{}
+	if frm.OtherReason == "" {
+		errs["other_reason"] = "Please explain"
+	}
+	if frm.Email == "" {
+		errs["email"] = "Email required for newsletter"
+	}
+	return errs, len(errs) == 0
+}
+
+func TestConditions(t *testing.T) {
+
+	s2f := New()
+	s2f.SetOptions("reason", []string{"price", "other"}, []string{"Price", "Other"})
+
+	tests := []struct {
+		in         condFormT
+		wantHidden []string // json names of hidden fields
+		wantErrs   int
+	}{
+		{
+			in:         condFormT{Reason: "price"},
+			wantHidden: []string{"other_reason", "email"},
+			wantErrs:   0,
+		},
+		{
+			in:         condFormT{Reason: "other"},
+			wantHidden: []string{"email"},
+			wantErrs:   1,
+		},
+		{
+			in:         condFormT{Reason: "other", Newsletter: true},
+			wantHidden: nil,
+			wantErrs:   2,
+		},
+	}
+
+	for idx, tt := range tests {
+
+		html := string(s2f.Form(tt.in))
+
+		if !strings.Contains(html, "data-s2f-showif='reason=other'") ||
+			!strings.Contains(html, "data-s2f-hideif='newsletter=false'") {
+			t.Errorf("idx%2v: condition attributes missing", idx)
+		}
+		if !strings.Contains(html, "function s2fConditions") {
+			t.Errorf("idx%2v: struc2frm.js missing", idx)
+		}
+		hiddenCnt := strings.Count(html, " hidden disabled>")
+		if hiddenCnt != len(tt.wantHidden) {
+			t.Errorf("idx%2v: %v hidden fieldsets - want %v", idx, hiddenCnt, len(tt.wantHidden))
+		}
+
+		errs, valid := s2f.Validate(tt.in)
+		if len(errs) != tt.wantErrs || valid != (tt.wantErrs == 0) {
+			t.Errorf("idx%2v: Validate() got %v %v - want %v errors", idx, errs, valid, tt.wantErrs)
+		}
+		for _, name := range tt.wantHidden {
+			if _, ok := errs[name]; ok {
+				t.Errorf("idx%2v: error for hidden field %v", idx, name)
+			}
+		}
+	}
+
+	// no script without conditions
+	if strings.Contains(string(s2f.Form(userDataFormT{})), "s2fConditions") {
+		t.Errorf("struc2frm.js should only be included for conditions")
+	}
+}
+
+type condBadT struct {
+	Note  string `json:"note"   form:"showif='other'"`
+	Note2 string `json:"note2"  form:"hideif='missing=x'"`
+}
+
+func TestConditionErrors(t *testing.T) {
+
+	s2f := New()
+
+	var fe *FieldError
+	if err := s2f.RenderForm(&strings.Builder{}, condBadT{}); !errors.As(err, &fe) || fe.Field != "Note" {
+		t.Errorf("RenderForm() should reject condition without '=' - got %v", err)
+	}
+
+	err := s2f.Check(condBadT{})
+	if err == nil ||
+		!strings.Contains(err.Error(), "expected field=value") ||
+		!strings.Contains(err.Error(), "unknown field 'missing'") {
+		t.Errorf("Check() got %v", err)
+	}
+}
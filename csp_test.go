@@ -0,0 +1,55 @@
+package struc2frm
+
+import (
+	"strings"
+	"testing"
+)
+
+type cspRowT struct {
+	Product string `json:"product"  form:"label-style='width:4rem'"`
+}
+
+type cspFormT struct {
+	Department string    `json:"department"  form:"subtype='select',onchange='true',label-style='width:9rem'"`
+	Items      []string  `json:"items"       form:"subtype='select',multiple='true',wildcardselect='true',autofocus='true'"`
+	Colors     []string  `json:"colors"      form:"subtype='checkboxgroup',label-style='color:red'"`
+	Comment    string    `json:"comment"     form:"subtype='textarea'"`
+	Rows       []cspRowT `json:"rows"        form:"max='2'"`
+}
+
+func TestCSPNonce(t *testing.T) {
+
+	s2f := New()
+	s2f.Indent = 80
+	s2f.CSPNonce = "r4nd0m"
+	s2f.SetOptions("department", []string{"ub", "fm"}, []string{"UB", "FM"})
+	s2f.SetOptions("items", []string{"anton", "berta"}, []string{"Anton", "Berta"})
+	s2f.SetOptions("colors", []string{"red", "blue"}, []string{"Red", "Blue"})
+
+	got := string(s2f.Form(cspFormT{}))
+
+	for _, forbidden := range []string{"onchange=", "oninput=", "javascript:", "<style>", "<script type=\"text/javascript\">", "style='"} {
+		if strings.Contains(got, forbidden) {
+			t.Errorf("CSP mode output contains %v", forbidden)
+		}
+	}
+	for _, tag := range []string{"<style", "<script"} {
+		total := strings.Count(got, tag)
+		nonced := strings.Count(got, tag+" nonce='r4nd0m'") + strings.Count(got, tag+" type=\"text/javascript\" nonce='r4nd0m'")
+		if total == 0 || total != nonced {
+			t.Errorf("%v of %v %v tags have a nonce", nonced, total, tag)
+		}
+	}
+	for _, want := range []string{
+		"data-s2f-submit='true'", "data-s2f-wildcard='true'", `ev.target.matches("[data-s2f-submit]")`,
+		"<label for='department' class='s2f-ls-0' >", ".s2f-ls-0 { width:9rem }",
+		"<legend class='group-legend s2f-ls-2' >", ".s2f-ls-2 { color:red }",
+		"<label for='items' class='s2f-valign-top' >", "<label for='comment' class='s2f-valign-top' >",
+		"class='s2f-ls-4-0' >", ".s2f-ls-4-0 { width:4rem }",
+		"<div class='s2f-vspace'>", "div.s2f-vspace { height: 0.6rem; }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CSP mode output lacks %v", want)
+		}
+	}
+}
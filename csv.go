@@ -10,27 +10,27 @@ import (
 func (s2f *s2FT) CSVLine(intf interface{}, sep string) string {
 
 	v := reflect.ValueOf(intf) // ifVal
-	typeOfS := v.Type()
 	// v = v.Elem()            // dereference
 
 	if v.Kind().String() != "struct" {
 		return fmt.Sprintf("struct2form.CSVLine() - arg1 must be struct - is %v", v.Kind())
 	}
 
-	values := make([]string, 0, v.NumField())
+	sch := schemaOf(v.Type())
 
-	for i := 0; i < v.NumField(); i++ {
+	values := make([]string, 0, len(sch.fields))
+
+	for _, fs := range sch.fields {
 
 		// struct field name; i.e. Name, Birthdate
-		fn := typeOfS.Field(i).Name
-		if fn[0:1] != strings.ToUpper(fn[0:1]) { // only used to find unexported fields; otherwise json tag name is used
+		if !fs.exported { // only used to find unexported fields; otherwise json tag name is used
 			continue // skip unexported
 		}
-		if strings.HasPrefix(fn, "Separator") {
+		if strings.HasPrefix(fs.name, "Separator") {
 			continue
 		}
 
-		val := v.Field(i).Interface()
+		val := v.Field(fs.index).Interface()
 		if valBool, ok := val.(bool); ok {
 			values = append(values, fmt.Sprintf("%v", valBool))
 		} else {
@@ -64,27 +64,27 @@ func (s2f *s2FT) CSVLine(intf interface{}, sep string) string {
 func (s2f *s2FT) HeaderRow(intf interface{}, sep string) string {
 
 	v := reflect.ValueOf(intf) // ifVal
-	typeOfS := v.Type()
 	// v = v.Elem()            // dereference
 
 	if v.Kind().String() != "struct" {
 		return fmt.Sprintf("struct2form.CSVLine() - arg1 must be struct - is %v", v.Kind())
 	}
 
-	headers := make([]string, 0, v.NumField())
+	sch := schemaOf(v.Type())
+
+	headers := make([]string, 0, len(sch.fields))
 
-	for i := 0; i < v.NumField(); i++ {
+	for _, fs := range sch.fields {
 
 		// struct field name; i.e. Name, Birthdate
-		fn := typeOfS.Field(i).Name
-		if fn[0:1] != strings.ToUpper(fn[0:1]) { // only used to find unexported fields; otherwise json tag name is used
+		if !fs.exported { // only used to find unexported fields; otherwise json tag name is used
 			continue // skip unexported
 		}
-		if strings.HasPrefix(fn, "Separator") {
+		if strings.HasPrefix(fs.name, "Separator") {
 			continue
 		}
 
-		headers = append(headers, fn)
+		headers = append(headers, fs.name)
 	}
 
 	w := &strings.Builder{}
@@ -39,6 +39,11 @@ func main() {
 		mux1.HandleFunc("/"+pfx+"/card/", struc2frm.CardH)
 	}
 
+	mux1.Handle("/struc2frm-assets/", struc2frm.AssetsHandler())
+	if pfx != "" {
+		mux1.Handle("/"+pfx+"/struc2frm-assets/", struc2frm.AssetsHandler())
+	}
+
 	mux4 := http.NewServeMux() // top router for non-middlewared handlers
 	mux4.Handle("/", mux1)
 
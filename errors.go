@@ -0,0 +1,196 @@
+package struc2frm
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/form"
+)
+
+// Errors returned by Decode(), DecodeMultipartForm() and ValidateFormToken();
+// check with errors.Is(err, ErrTokenExpired) etc.;
+// error texts contain no HTML and no request contents.
+var (
+	ErrMissingToken = errors.New("struc2frm: request has form values, but no form token")
+	ErrTokenExpired = errors.New("struc2frm: form token expired - reload the form")
+	ErrTokenInvalid = errors.New("struc2frm: form token invalid")
+
+	ErrHoneypotFilled   = errors.New("struc2frm: honeypot input was filled - submission by bot")
+	ErrSubmittedTooFast = errors.New("struc2frm: form submitted faster than MinFillTime - submission by bot")
+)
+
+// ErrNotStruct is returned by RenderForm() and RenderCard()
+// for arguments other than struct values
+var ErrNotStruct = errors.New("struc2frm: arg1 must be struct")
+
+// ErrWizardState is returned by Wizard.Decode()
+// for a missing, forged or undecodable wizard state
+var ErrWizardState = errors.New("struc2frm: wizard state invalid")
+
+// Errors of uploaded files - as DecodeError.Err of DecodeMultipartForm()
+// for the 'form' tags maxsize and accept; ErrNoFile from FileUpload.Open()
+var (
+	ErrFileTooLarge = errors.New("struc2frm: uploaded file exceeds maxsize")
+	ErrFileType     = errors.New("struc2frm: uploaded file type not accepted")
+	ErrNoFile       = errors.New("struc2frm: no file uploaded")
+)
+
+// ErrOptionsMismatch is returned by SetOptions()
+// for keys and labels of different length
+var ErrOptionsMismatch = errors.New("struc2frm: keys and labels length does not match")
+
+// errWriter keeps the first write error;
+// subsequent writes are skipped
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+// DecodeError describes a request value
+// which could not be converted into its struct field;
+// i.e. 'abc' for field 'groups int'.
+type DecodeError struct {
+	Field string // json name of the struct field; i.e. date_layout
+	Value string // submitted value
+	Err   error  // per-field error from go-playground/form
+
+	kind reflect.Kind // of the struct field or its slice elements; for Message()
+	hint string       // maxsize or accept of rejected uploads; for Message()
+}
+
+func (de *DecodeError) Error() string {
+	return fmt.Sprintf("struc2frm: cannot decode field '%v': %v", de.Field, de.Err)
+}
+
+// Unwrap gives errors.Is() and errors.As() access to the go-playground/form error
+func (de *DecodeError) Unwrap() error {
+	return de.Err
+}
+
+// Message is a user facing text for display beside the input;
+// i.e. 'must be a number'
+func (de *DecodeError) Message() string {
+	if msg := uploadMessage(de); msg != "" {
+		return msg
+	}
+	switch de.kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.Bool:
+		return "must be true or false"
+	}
+	return "invalid value"
+}
+
+// DecodeErrors contains one DecodeError per struct field - keyed by json name;
+// errors.As(err, &decodeErr) yields the first one.
+type DecodeErrors map[string]*DecodeError
+
+// Fields returns the json names of the failed fields in stable order
+func (des DecodeErrors) Fields() []string {
+	fields := make([]string, 0, len(des))
+	for field := range des {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Messages returns Message() by json name - suitable for s2f.AddErrors()
+func (des DecodeErrors) Messages() map[string]string {
+	msgs := map[string]string{}
+	for field, de := range des {
+		msgs[field] = de.Message()
+	}
+	return msgs
+}
+
+func (des DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(des))
+	for _, field := range des.Fields() {
+		msgs = append(msgs, des[field].Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// As makes errors.As() work for a single *DecodeError target
+func (des DecodeErrors) As(target interface{}) bool {
+	tgt, ok := target.(**DecodeError)
+	if !ok || len(des) == 0 {
+		return false
+	}
+	*tgt = des[des.Fields()[0]]
+	return true
+}
+
+// newDecodeErrors converts the go-playground/form errors
+// into DecodeErrors; other errors are returned unchanged
+func newDecodeErrors(err error, vals map[string][]string, ptr2Struct interface{}) error {
+	fErrs, ok := err.(form.DecodeErrors)
+	if !ok {
+		return err
+	}
+	des := DecodeErrors{}
+	for field, fErr := range fErrs {
+		des[field] = &DecodeError{
+			Field: field,
+			Value: strings.Join(vals[field], ","),
+			Err:   fErr,
+			kind:  fieldKind(ptr2Struct, field),
+		}
+	}
+	return des
+}
+
+// fieldKind finds the kind of a top level struct field by json name;
+// for slices the kind of the elements
+func fieldKind(ptr2Struct interface{}, nameJSON string) reflect.Kind {
+	typeOfS := reflect.Indirect(reflect.ValueOf(ptr2Struct)).Type()
+	if typeOfS.Kind() != reflect.Struct {
+		return reflect.Invalid
+	}
+	for i := 0; i < typeOfS.NumField(); i++ {
+		inpName := typeOfS.Field(i).Tag.Get("json") // i.e. date_layout
+		inpName = strings.Replace(inpName, ",omitempty", "", -1)
+		if inpName != nameJSON {
+			continue
+		}
+		tp := typeOfS.Field(i).Type
+		if tp.Kind() == reflect.Slice {
+			return tp.Elem().Kind()
+		}
+		return tp.Kind()
+	}
+	return reflect.Invalid
+}
+
+// AddDecodeErrors adds the errors from Decode() or DecodeMultipartForm();
+// DecodeErrors are shown beside their inputs,
+// any other error on top of form.
+func (s2f *s2FT) AddDecodeErrors(err error) {
+	if err == nil {
+		return
+	}
+	var des DecodeErrors
+	if errors.As(err, &des) {
+		s2f.AddErrors(des.Messages())
+		return
+	}
+	s2f.AddError("global", template.HTMLEscapeString(err.Error()))
+}
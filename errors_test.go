@@ -0,0 +1,169 @@
+package struc2frm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateFormToken(t *testing.T) {
+
+	s2f := New()
+
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{
+			in:   s2f.FormToken(),
+			want: nil,
+		},
+		{
+			in:   "",
+			want: ErrMissingToken,
+		},
+		{
+			in:   s2f.tok(-s2f.FormTimeout - 2),
+			want: ErrTokenExpired,
+		},
+		{
+			in:   "abc",
+			want: ErrTokenInvalid,
+		},
+	}
+	for idx, tt := range tests {
+		got := s2f.ValidateFormToken(tt.in)
+		if !errors.Is(got, tt.want) {
+			t.Errorf("idx%2v: %-16v is %-16v should be %v", idx, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+
+	data := url.Values{}
+	data.Set("groups", "abc")
+	data.Set("hashkey", "xyz")
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	frm := entryForm{}
+	populated, err := Decode(req, &frm)
+	if populated || !errors.Is(err, ErrMissingToken) {
+		t.Errorf("want unpopulated and ErrMissingToken - got %v %v", populated, err)
+	}
+
+	// page views with query params, but without token
+	req, _ = http.NewRequest("GET", "/?utm_source=news&debug=1", nil)
+	populated, err = Decode(req, &frm)
+	if populated || err != nil {
+		t.Errorf("tokenless GET: want unpopulated and no error - got %v %v", populated, err)
+	}
+
+	data.Set("token", New().FormToken())
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	frm = entryForm{}
+	populated, err = Decode(req, &frm)
+	if !populated {
+		t.Errorf("want populated form")
+	}
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("want DecodeError - got %v", err)
+	}
+	if de.Field != "groups" || de.Value != "abc" {
+		t.Errorf("want field 'groups' with value 'abc' - got %v %v", de.Field, de.Value)
+	}
+	if strings.ContainsAny(err.Error(), "<>") {
+		t.Errorf("error must not contain HTML: %v", err)
+	}
+	if frm.HashKey != "xyz" {
+		t.Errorf("valid fields should still be decoded - got %q", frm.HashKey)
+	}
+}
+
+func TestDecodeErrorsBesideInput(t *testing.T) {
+
+	data := url.Values{}
+	data.Set("groups", "abc")
+	data.Set("hashkey", "keep-me")
+	data.Set("token", New().FormToken())
+
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	http.HandlerFunc(FormH).ServeHTTP(w, req)
+
+	body := w.Body.String()
+	want := `	<p class='error-block' id='groups_err' >must be a number</p>
+	<label for='groups' style='' >Groups</label>`
+	if !strings.Contains(body, want) {
+		t.Errorf("want error beside input groups")
+	}
+	if !strings.Contains(body, `value='keep-me'`) {
+		t.Errorf("want other entries to be kept")
+	}
+	if strings.Contains(body, "<pre>") {
+		t.Errorf("want no dump of the request")
+	}
+}
+
+type badTagT struct {
+	Name string `json:"name" form:"maxlength='42"`
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestRenderErrors(t *testing.T) {
+
+	s2f := New()
+
+	tests := []struct {
+		in      interface{}
+		wantNot bool // ErrNotStruct
+		wantTag bool // *FieldError wrapping *TagError
+	}{
+		{in: 42, wantNot: true},
+		{in: &entryForm{}, wantNot: true},
+		{in: badTagT{}, wantTag: true},
+	}
+	for idx, tt := range tests {
+		for _, render := range []func(io.Writer, interface{}) error{s2f.RenderForm, s2f.RenderCard} {
+			w := &bytes.Buffer{}
+			err := render(w, tt.in)
+			if errors.Is(err, ErrNotStruct) != tt.wantNot {
+				t.Errorf("idx%2v: ErrNotStruct expected %v - got %v", idx, tt.wantNot, err)
+			}
+			var fe *FieldError
+			var te *TagError
+			if (errors.As(err, &fe) && errors.As(err, &te)) != tt.wantTag {
+				t.Errorf("idx%2v: *FieldError/*TagError expected %v - got %v", idx, tt.wantTag, err)
+			}
+			if w.Len() > 0 {
+				t.Errorf("idx%2v: nothing should be written on error - got %q", idx, w.String())
+			}
+		}
+		// wrappers render the error as escaped text
+		got := string(s2f.Form(tt.in))
+		if !strings.HasPrefix(got, "struct2form.Form() - ") || strings.Contains(got, "'42") {
+			t.Errorf("idx%2v: Form() got %q", idx, got)
+		}
+	}
+
+	if err := s2f.RenderForm(failWriter{}, entryForm{}); err == nil || err.Error() != "connection reset" {
+		t.Errorf("RenderForm() should return the write error - got %v", err)
+	}
+	if err := s2f.RenderCard(failWriter{}, entryForm{}); err == nil {
+		t.Errorf("RenderCard() should return the write error")
+	}
+}
@@ -0,0 +1,58 @@
+package struc2frm
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// flashCookieName is the name of the cookie carrying the signed flash message
+const flashCookieName = "struc2frm_flash"
+
+// flashMaxAge is the lifetime of the flash cookie in seconds -
+// long enough for the redirect
+const flashMaxAge = 60
+
+// flashPurpose prefixes the signed message -
+// so that neither wizard states nor bot guard timestamps pass as flash cookies
+const flashPurpose = "flash:"
+
+// SetFlash stores msg in a signed cookie - for the request after a redirect;
+// call it before http.Redirect(); see ReadFlash().
+func (s2f *s2FT) SetFlash(w http.ResponseWriter, r *http.Request, msg string) {
+	enc := base64.RawURLEncoding.EncodeToString([]byte(msg))
+	http.SetCookie(w, &http.Cookie{
+		Name:     flashCookieName,
+		Value:    enc + "." + s2f.sign(flashPurpose+enc),
+		Path:     "/",
+		MaxAge:   flashMaxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ReadFlash reads and deletes the flash cookie of SetFlash();
+// Form() and Card() render the message above the form - see AddMessage() with key 'global';
+// forged cookies are ignored.
+func (s2f *s2FT) ReadFlash(w http.ResponseWriter, r *http.Request) string {
+	ck, err := r.Cookie(flashCookieName)
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{Name: flashCookieName, Path: "/", MaxAge: -1})
+
+	enc, sig, ok := strings.Cut(ck.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s2f.sign(flashPurpose+enc))) {
+		s2f.logger().Warn("flash cookie signature invalid")
+		return ""
+	}
+	msg, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return ""
+	}
+	s2f.AddMessage("global", LevelInfo, template.HTMLEscapeString(string(msg)))
+	return string(msg)
+}
@@ -0,0 +1,86 @@
+package struc2frm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFlash(t *testing.T) {
+
+	s2f := New()
+	rec := httptest.NewRecorder()
+	s2f.SetFlash(rec, httptest.NewRequest("POST", "/", nil), "Saved <Jane>")
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly || cookies[0].MaxAge != flashMaxAge {
+		t.Fatalf("flash cookie %+v", cookies)
+	}
+
+	forged := *cookies[0]
+	forged.Value = "U2F2ZWQ." + strings.Repeat("0", 64)
+
+	// signed without purpose - i.e. like the bot guard timestamps
+	unbound := *cookies[0]
+	enc, _, _ := strings.Cut(unbound.Value, ".")
+	unbound.Value = enc + "." + s2f.sign(enc)
+
+	tests := []struct {
+		cookie *http.Cookie
+		want   string
+	}{
+		{cookies[0], "Saved <Jane>"},
+		{&forged, ""},
+		{&unbound, ""},
+		{nil, ""},
+	}
+	for idx, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.cookie != nil {
+			req.AddCookie(tt.cookie)
+		}
+		rec := httptest.NewRecorder()
+		s2f := New()
+		if got := s2f.ReadFlash(rec, req); got != tt.want {
+			t.Errorf("idx%2v: got %q - want %q", idx, got, tt.want)
+		}
+		if tt.cookie != nil && !strings.Contains(rec.Header().Get("Set-Cookie"), "Max-Age=0") {
+			t.Errorf("idx%2v: flash cookie not deleted: %q", idx, rec.Header().Get("Set-Cookie"))
+		}
+		for _, html := range []string{string(s2f.Form(userDataFormT{})), string(s2f.Card(userDataFormT{}))} {
+			if hasFlash := strings.Contains(html, "<p class='global-info' role='status' >Saved &lt;Jane&gt;</p>"); hasFlash != (tt.want != "") {
+				t.Errorf("idx%2v: flash rendered %v", idx, hasFlash)
+			}
+		}
+	}
+}
+
+func TestHandlerFlash(t *testing.T) {
+
+	h := NewHandler(New(), func(ctx context.Context, frm *signupFormT) error { return nil })
+	h.SuccessMsg = "Thank you for signing up"
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return s2f.SetOptions("plan", []string{"free"}, []string{"Free"})
+	}
+
+	data := url.Values{}
+	data.Set("token", New().FormToken())
+	data.Set("name", "Jane")
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther || len(rec.Result().Cookies()) != 1 {
+		t.Fatalf("status %v - cookies %v", rec.Code, rec.Result().Cookies())
+	}
+
+	req = httptest.NewRequest("GET", rec.Header().Get("Location"), nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), ">Thank you for signing up</p>") {
+		t.Errorf("flash missing after redirect")
+	}
+}
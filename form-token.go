@@ -3,9 +3,7 @@ package struc2frm
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
 	"io"
-	"log"
 	"time"
 )
 
@@ -15,12 +13,12 @@ import (
 var fixedLocation = time.FixedZone("UTC_-2", -2*60*60)
 
 // tok rounds time to hours
-// and computes a hash from it
-func tok(hoursOffset int, salt string) string {
+// and computes a hash from it and s2f.Salt
+func (s2f *s2FT) tok(hoursOffset int) string {
 	hasher := sha256.New()
-	_, err := io.WriteString(hasher, salt)
+	_, err := io.WriteString(hasher, s2f.Salt)
 	if err != nil {
-		log.Printf("Error writing salt to hasher: %v", err)
+		s2f.logger().Error("writing salt to hasher", "err", err)
 	}
 	t := time.Now().In(fixedLocation)
 	if hoursOffset != 0 {
@@ -29,7 +27,7 @@ func tok(hoursOffset int, salt string) string {
 	// log.Printf("token time: %v", t.Format("02.01.2006 15"))
 	_, err = io.WriteString(hasher, t.Format("02.01.2006 15"))
 	if err != nil {
-		log.Printf("Error writing date-hour to hasher: %v", err)
+		s2f.logger().Error("writing date-hour to hasher", "err", err)
 	}
 	hash := hasher.Sum(nil)
 	return hex.EncodeToString(hash)
@@ -39,9 +37,13 @@ func tok(hoursOffset int, salt string) string {
 // User independent.
 // Should we add the user name into the hashed base?
 func (s2f *s2FT) FormToken() string {
-	return tok(0, s2f.Salt)
+	return s2f.tok(0)
 }
 
+// tokenExpiredHorizon is the number of hours beyond FormTimeout,
+// for which an outdated token is reported as ErrTokenExpired instead of ErrTokenInvalid
+const tokenExpiredHorizon = 48
+
 // ValidateFormToken checks tokens
 // against current hour - back to n previous hours.
 // Plus one more for bounding glitches / border crossing
@@ -50,15 +52,25 @@ func (s2f *s2FT) FormToken() string {
 // FormTimeout := 2
 // lower bound := -4
 // => Checking token against current hour, previous hour, second previous hour, third previous hour
+//
+// Returns ErrMissingToken, ErrTokenExpired or ErrTokenInvalid.
 func (s2f *s2FT) ValidateFormToken(arg string) error {
+	if arg == "" {
+		return ErrMissingToken
+	}
 	lowerBound := s2f.FormTimeout*-1 - 1
 	for i := 0; i >= lowerBound; i-- {
-		if arg == tok(i, s2f.Salt) {
+		if arg == s2f.tok(i) {
 			return nil
 		}
 	}
-	if arg == tok(1, s2f.Salt) {
+	if arg == s2f.tok(1) {
 		return nil
 	}
-	return fmt.Errorf("form token not issued within last two hours - reload")
+	for i := lowerBound - 1; i >= lowerBound-tokenExpiredHorizon; i-- {
+		if arg == s2f.tok(i) {
+			return ErrTokenExpired
+		}
+	}
+	return ErrTokenInvalid
 }
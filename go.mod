@@ -1,9 +1,7 @@
 module github.com/pbberlin/struc2frm
 
-go 1.13
+go 1.21
 
-require (
-	github.com/go-playground/form v3.1.4+incompatible
-	github.com/pkg/errors v0.9.1
-	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
-)
+require github.com/go-playground/form v3.1.4+incompatible
+
+require gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
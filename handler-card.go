@@ -38,7 +38,7 @@ func CardH(w http.ResponseWriter, req *http.Request) {
 
 	fmt.Fprintf(
 		w,
-		defaultHTML,
+		defaultHTML(),
 		s2f.Card(frm),
 		"",
 	)
@@ -2,6 +2,7 @@ package struc2frm
 
 import (
 	"fmt"
+	"html/template"
 	"log"
 	"net/http"
 )
@@ -9,7 +10,8 @@ import (
 type uploadFormT struct {
 	TextField string `json:"text_field,omitempty"   form:"maxlength='16',size='16'"`
 	// Requires distinct way of form parsing
-	Upload []byte `json:"upload,omitempty"          form:"accesskey='u',accept='.txt',suffix='*.txt files'"`
+	Upload     []byte `json:"upload,omitempty"          form:"accesskey='u',accept='.txt',suffix='*.txt files',filename='upload_name'"`
+	UploadName string `json:"upload_name,omitempty"     form:"-"` // populated by DecodeMultipartForm()
 }
 
 func (frm uploadFormT) Validate() (map[string]string, bool) {
@@ -31,20 +33,14 @@ func FileUploadH(w http.ResponseWriter, req *http.Request) {
 
 	populated, err := DecodeMultipartForm(req, &frm)
 	if populated && err != nil {
-		s2f.AddError("global", fmt.Sprintf("cannot decode multipart form: %v<br>\n <pre>%v</pre>", err, indentedDump(req.Form)))
-		log.Printf("cannot decode multipart form: %v<br>\n <pre>%v</pre>", err, indentedDump(req.Form))
-	}
-
-	bts, excelFileName, err := ExtractUploadedFile(req)
-	if err != nil {
-		s2f.AddError("global", fmt.Sprintf("Cannot extract file from POST form: %v<br>\n", err))
+		s2f.AddDecodeErrors(err) // conversion errors beside their inputs
+		log.Printf("cannot decode multipart form: %v", err)
 	}
 
 	fileMsg := ""
-	// if len(bts) > 0 && excelFileName != "" {
 	if populated {
-		fileMsg = fmt.Sprintf("%v bytes read from excel file -%v- <br>\n", len(bts), excelFileName)
-		fileMsg = fmt.Sprintf("%vFile content is --%v-- <br>\n", fileMsg, string(bts))
+		fileMsg = fmt.Sprintf("%v bytes read from excel file -%v- <br>\n", len(frm.Upload), template.HTMLEscapeString(frm.UploadName))
+		fileMsg = fmt.Sprintf("%vFile content is --%v-- <br>\n", fileMsg, template.HTMLEscapeString(string(frm.Upload)))
 	} else {
 		fileMsg = "No upload filename - or empty file<br>\n"
 
@@ -52,7 +48,7 @@ func FileUploadH(w http.ResponseWriter, req *http.Request) {
 
 	fmt.Fprintf(
 		w,
-		defaultHTML,
+		defaultHTML(),
 		s2f.Form(frm),
 		fileMsg,
 	)
@@ -66,7 +66,7 @@ func TestFileUpload(t *testing.T) {
 	<input type='text' name='text_field' id='text_field' value='posted-text'  maxlength='16' size='16' />
 	<div style='height:0.6rem'>&nbsp;</div>
 	<label for='upload' style='' ><u>U</u>pload</label>
-	<input type='file'   name='upload'     id='upload'     value='ignored.json'  accesskey='u' accept='.txt' /><span class='postlabel' >*.txt files</span>
+	<input type='file'   name='upload'     id='upload'     value='ignored.json'  accesskey='u' accept='.txt' aria-describedby='upload_sfx' /><span class='s2f-upload-name' id='upload_file' >previously: upload-file.txt - please select again</span><span class='postlabel' id='upload_sfx' >*.txt files</span>
 	<div style='height:0.6rem'>&nbsp;</div>
 	<button  type='submit' name='btnSubmit' value='1' accesskey='s'  ><b>S</b>ubmit</button>
 	<div style='height:0.6rem'>&nbsp;</div>
@@ -96,8 +96,8 @@ func FormH(w http.ResponseWriter, req *http.Request) {
 	// pulling in values from http request
 	populated, err := Decode(req, &frm)
 	if populated && err != nil {
-		s2f.AddError("global", fmt.Sprintf("cannot decode form: %v<br>\n <pre>%v</pre>", err, indentedDump(req.Form)))
-		log.Printf("cannot decode form: %v<br>\n <pre>%v</pre>", err, indentedDump(req.Form))
+		s2f.AddDecodeErrors(err) // conversion errors beside their inputs
+		log.Printf("cannot decode form: %v", err)
 	}
 
 	// init values - multiple
@@ -138,6 +138,9 @@ func FormH(w http.ResponseWriter, req *http.Request) {
 		salt2 := "dudoedeldu"
 
 		num, _ := strconv.Atoi(req.FormValue("groups"))
+		if num < 1 {
+			num = 1 // i.e. 'abc' - decode error shown beside input
+		}
 		items := strings.Split(req.FormValue("items"), "\n")
 		for i := 0; i < len(items); i++ {
 			items[i] = strings.TrimSpace(items[i])
@@ -184,7 +187,7 @@ func FormH(w http.ResponseWriter, req *http.Request) {
 
 	fmt.Fprintf(
 		w,
-		defaultHTML,
+		defaultHTML(),
 		s2f.Form(frm),
 		binsF,
 	)
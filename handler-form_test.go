@@ -74,29 +74,36 @@ func test(t *testing.T, method string) {
 	expected := `<h3>Entry form</h3>
 <form name='frmMain'  action=''  method='POST' >
 	<input name='token'    type='hidden'   value='%v' />
-	<p class='error-block' >Missing department</p>
+	<div class='error-summary' role='alert' aria-labelledby='frmMain_errsum' >
+		<p id='frmMain_errsum' >There are 2 errors</p>
+		<ul>
+			<li><a href='#department' >Department/Abteilung: Missing department</a></li>
+			<li><a href='#check_this' >Check this: You need to comply</a></li>
+		</ul>
+	</div>
+	<p class='error-block' id='department_err' >Missing department</p>
 	<label for='department' style='' >De<u>p</u>artment/Abteilung</label>
 	<div class='select-arrow'>
-	<select name='department' id='department'  subtype='select' accesskey='p' onchange='javascript:this.form.submit();' title='loading items' />
+	<select name='department' id='department'  subtype='select' accesskey='p' onchange='javascript:this.form.submit();' title='loading items' aria-invalid='true' aria-describedby='department_err department_title' />
 		<option value='ub'          >UB</option>
 		<option value='fm'          >FM</option>
 	</select>
-	</div>
+	</div><span class='s2f-sr-only' id='department_title' >loading items</span>
 	<div style='height:0.6rem'>&nbsp;</div>
 	<div  class='separator'></div>
 	<label for='hashkey' style='' >Hashkey</label>
-	<input type='text' name='hashkey' id='hashkey' value='%v'  maxlength='16' size='16' autocapitalize='off' /><span class='postlabel' >salt, changes randomness</span>
+	<input type='text' name='hashkey' id='hashkey' value='%v'  maxlength='16' size='16' autocapitalize='off' aria-describedby='hashkey_sfx' /><span class='postlabel' id='hashkey_sfx' >salt, changes randomness</span>
 	<div style='height:0.6rem'>&nbsp;</div>
 	<label for='groups' style='' >Groups</label>
 	<input type='number' name='groups' id='groups' value='%v'  min=1 max='100' maxlength='3' size='3' />
 	<div style='height:0.6rem'>&nbsp;</div>
 	<label for='items' style='vertical-align: top;' >Textarea of<br>line items</label>
-	<textarea name='items' id='items'  subtype='textarea' cols='22' rows='4' maxlength='4000' title='add times - delimited by newline (enter)' />Brutsyum, Zusoh
+	<textarea name='items' id='items'  subtype='textarea' cols='22' rows='4' maxlength='4000' title='add times - delimited by newline (enter)' aria-describedby='items_title' />Brutsyum, Zusoh
 Dovosuke, Udsyuke
 Fyrkros, Loekyo
 Gyaffsydu, Loekusde
 Heyos, Ysyr
-Rtoynbsonnos, Tars</textarea>
+Rtoynbsonnos, Tars</textarea><span class='s2f-sr-only' id='items_title' >add times - delimited by newline (enter)</span>
 	<div style='height:0.6rem'>&nbsp;</div>
 	<label for='items2' style='vertical-align: top;' >Multi<br>select<br>dropdown</label>
 	<div class='select-arrow'>
@@ -119,22 +126,18 @@ Rtoynbsonnos, Tars</textarea>
 	<label for='date_layout' style='' >Layou<u>t</u> of the date</label>
 	<input type='text' name='date_layout' id='date_layout' value=''  accesskey='t' maxlength='16' size='16' pattern='[0-9\.\-/]{2,10}' placeholder='2006/01/02 15:04' />
 	<div style='height:0.6rem'>&nbsp;</div>
-	<p class='error-block' >You need to comply</p>
+	<p class='error-block' id='check_this_err' >You need to comply</p>
 	<label for='check_this' style='' >Check this</label>
-	<input type='checkbox' name='check_this' id='check_this' value='true'   />
-	<input type='hidden' name='check_this' value='false' /><span class='postlabel' >without consequence</span>
+	<input type='checkbox' name='check_this' id='check_this' value='true'   aria-invalid='true' aria-describedby='check_this_err check_this_sfx' />
+	<input type='hidden' name='check_this' value='false' /><span class='postlabel' id='check_this_sfx' >without consequence</span>
 	<div style='height:0.6rem'>&nbsp;</div>
-	<label for='fruit' style='' >Fruit</label>
-	<div class='select-arrow'>
-	<div class='radio-group'>
-		<label for='fruit' >Pear</label>
-		<input type='radio' name='fruit' value='pear'  />
-		<label for='fruit' >Plum</label>
-		<input type='radio' name='fruit' value='plum'  />
-		<label for='fruit' >Peach</label>
-		<input type='radio' name='fruit' value='peach' checked="checked" />
-		<input type='radio' name='fruit' value='noanswer'  />
-	</div>	</div><span class='postlabel' >like dropdown</span>
+	<fieldset class='radio-group' role='radiogroup' id='fruit' aria-describedby='fruit_sfx' >
+	<legend class='group-legend' style='' >Fruit</legend>
+		<span class='radio-item'><input type='radio' name='fruit' id='fruit_0' value='pear' /><label for='fruit_0' >Pear</label></span>
+		<span class='radio-item'><input type='radio' name='fruit' id='fruit_1' value='plum' /><label for='fruit_1' >Plum</label></span>
+		<span class='radio-item'><input type='radio' name='fruit' id='fruit_2' value='peach' checked /><label for='fruit_2' >Peach</label></span>
+		<span class='radio-item'><input type='radio' name='fruit' id='fruit_3' value='noanswer' aria-label='noanswer' /></span>
+	</fieldset><span class='postlabel' id='fruit_sfx' >like dropdown</span>
 	<div style='height:0.6rem'>&nbsp;</div>
 </fieldset>
 	<button  type='submit' name='btnSubmit' value='1' accesskey='s'  ><b>S</b>ubmit</button>
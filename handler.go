@@ -0,0 +1,171 @@
+package struc2frm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+)
+
+// Handler serves the form for struct type T;
+// GET and HEAD render the form with the init values - the form is always posted;
+// POST decodes and validates the form - see s2f.Validate() -
+// and calls OnValid; on success, it redirects - Post/Redirect/Get -
+// with SuccessMsg as flash message;
+// otherwise the form is rendered again - with the errors.
+//
+//	h := struc2frm.NewHandler(s2f, func(ctx context.Context, frm *entryForm) error {
+//		return store(ctx, frm)
+//	})
+//	mux.Handle("/entry", h)
+type Handler[T any] struct {
+	Converter *s2FT // cloned per request
+
+	Init    func(r *http.Request) T                            // init values; nil yields the zero value of T
+	Options func(ctx context.Context, s2f OptionsSetter) error // loads options per request; i.e. SetOptions() from a database
+	OnValid func(ctx context.Context, frm *T) error            // processes valid forms; errors are shown on top of the form
+
+	Page       *template.Template // executed with HandlerPage; nil renders tpl-main.html
+	SuccessURL string             // redirect target after OnValid; default is the request URL
+	SuccessMsg string             // flash message shown after the redirect; see SetFlash()
+}
+
+// OptionsSetter is the converter - as passed to Handler.Options
+type OptionsSetter interface {
+	SetOptions(nameJSON string, keys, labels []string) error
+	SetOptionsSource(nameJSON string, src interface{}) error
+	SetOptionsProvider(nameJSON string, p OptionsProvider)
+}
+
+// HandlerPage is passed to Handler.Page
+type HandlerPage struct {
+	Form    template.HTML
+	Request *http.Request
+}
+
+// NewHandler creates a Handler for struct type T - rendered by s2f
+func NewHandler[T any](s2f *s2FT, onValid func(ctx context.Context, frm *T) error) *Handler[T] {
+	return &Handler[T]{Converter: s2f, OnValid: onValid}
+}
+
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	s2f := New()
+	if h.Converter != nil {
+		s2f = h.Converter.CloneForRequest()
+	}
+	s2f.Method = http.MethodPost // submissions are only processed from POST
+	lg := s2f.logger()
+
+	if h.Options != nil {
+		if err := h.Options(ctx, s2f); err != nil {
+			lg.Error("loading options", "err", err)
+			http.Error(w, "form options cannot be loaded", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var frm T
+	if h.Init != nil {
+		frm = h.Init(r)
+	}
+
+	// GET and HEAD only render - a submission in the query would be decoded again
+	// after the redirect to the request URL
+	if r.Method != http.MethodPost {
+		s2f.ReadFlash(w, r)
+		h.render(w, r, s2f, frm)
+		return
+	}
+
+	// inputs are posted completely - reset them to prevent accumulation of slice values;
+	// init values remain for unexported and skipped fields
+	if v := reflect.ValueOf(&frm).Elem(); v.Kind() == reflect.Struct {
+		for _, fs := range schemaOf(v.Type()).fields {
+			if fs.exported && !fs.skip {
+				v.Field(fs.index).Set(reflect.Zero(v.Field(fs.index).Type()))
+			}
+		}
+	}
+
+	populated, err := h.decode(s2f, r, &frm)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		lg.Info("request too large", "limit", tooLarge.Limit)
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	case populated && err != nil:
+		s2f.AddDecodeErrors(err) // conversion errors beside their inputs
+		lg.Info("cannot decode form", "err", err)
+	case populated:
+		errs, valid := s2f.Validate(frm)
+		if !valid {
+			s2f.AddErrors(errs)
+			break
+		}
+		if h.OnValid != nil {
+			if err := h.OnValid(ctx, &frm); err != nil {
+				s2f.AddError("global", template.HTMLEscapeString(err.Error()))
+				lg.Info("processing valid form", "err", err)
+				break
+			}
+		}
+		target := h.SuccessURL
+		if target == "" {
+			target = r.URL.RequestURI()
+		}
+		if h.SuccessMsg != "" {
+			s2f.SetFlash(w, r, h.SuccessMsg)
+		}
+		http.Redirect(w, r, target, http.StatusSeeOther)
+		return
+	}
+
+	h.render(w, r, s2f, frm)
+}
+
+// decode decodes posted multipart forms for structs with file inputs
+func (h *Handler[T]) decode(s2f *s2FT, r *http.Request, frm *T) (populated bool, err error) {
+	tp := reflect.TypeOf(frm).Elem()
+	if tp.Kind() == reflect.Struct && schemaOf(tp).upload {
+		return s2f.DecodeMultipartForm(r, frm)
+	}
+	return s2f.Decode(r, frm)
+}
+
+// render writes the page; nothing is written, if the form cannot be rendered
+func (h *Handler[T]) render(w http.ResponseWriter, r *http.Request, s2f *s2FT, frm T) {
+
+	form := &bytes.Buffer{}
+	if err := s2f.RenderFormContext(r.Context(), form, frm); err != nil {
+		s2f.logger().Error("rendering form", "err", err)
+		http.Error(w, "form cannot be rendered", http.StatusInternalServerError)
+		return
+	}
+
+	page := &bytes.Buffer{}
+	if h.Page == nil {
+		fmt.Fprintf(page, defaultHTML(), template.HTML(form.String()), "")
+	} else {
+		err := h.Page.Execute(page, HandlerPage{Form: template.HTML(form.String()), Request: r})
+		if err != nil {
+			s2f.logger().Error("executing page template", "err", err)
+			http.Error(w, "page cannot be rendered", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page.Bytes())
+}
@@ -0,0 +1,196 @@
+package struc2frm
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type signupFormT struct {
+	Name  string `json:"name"`
+	Plan  string `json:"plan"   form:"subtype='select'"`
+	Seats int    `json:"seats"`
+}
+
+func (frm signupFormT) Validate() (map[string]string, bool) {
+	errs := map[string]string{}
+	if frm.Name == "" {
+		errs["name"] = "Please enter a name"
+	}
+	return errs, len(errs) == 0
+}
+
+func TestHandler(t *testing.T) {
+
+	stored := []signupFormT{}
+	h := NewHandler(New(), func(ctx context.Context, frm *signupFormT) error {
+		if frm.Name == "taken" {
+			return errors.New("name <taken> is not available")
+		}
+		stored = append(stored, *frm)
+		return nil
+	})
+	h.Init = func(r *http.Request) signupFormT {
+		return signupFormT{Seats: 5}
+	}
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return s2f.SetOptions("plan", []string{"free", "pro"}, []string{"Free", "Pro"})
+	}
+
+	tests := []struct {
+		method   string
+		vals     map[string]string
+		wantCode int
+		want     string
+		stored   int
+	}{
+		{"GET", nil, http.StatusOK, "value='5'", 0},
+		{"GET", nil, http.StatusOK, ">Pro</option>", 0},
+		{"POST", map[string]string{"name": "", "plan": "pro", "seats": "3"}, http.StatusOK, "Please enter a name", 0},
+		{"POST", map[string]string{"name": "Jane", "seats": "abc"}, http.StatusOK, "must be a number", 0},
+		{"POST", map[string]string{"name": "taken", "seats": "3"}, http.StatusOK, "name &lt;taken&gt; is not available", 0},
+		{"POST", map[string]string{"name": "Jane", "plan": "pro", "seats": "3"}, http.StatusSeeOther, "", 1},
+		{"PUT", nil, http.StatusMethodNotAllowed, "", 1},
+	}
+
+	for idx, tt := range tests {
+		data := url.Values{}
+		data.Set("token", New().FormToken())
+		for key, val := range tt.vals {
+			data.Set(key, val)
+		}
+		req := httptest.NewRequest(tt.method, "/signup?src=ad", nil)
+		if tt.method == "POST" {
+			req = httptest.NewRequest(tt.method, "/signup?src=ad", strings.NewReader(data.Encode()))
+			req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("idx%2v: status %v - want %v", idx, rec.Code, tt.wantCode)
+		}
+		if !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("idx%2v: missing %q", idx, tt.want)
+		}
+		if len(stored) != tt.stored {
+			t.Errorf("idx%2v: %v stored - want %v", idx, len(stored), tt.stored)
+		}
+		if rec.Code == http.StatusSeeOther && rec.Header().Get("Location") != "/signup?src=ad" {
+			t.Errorf("idx%2v: redirect to %q", idx, rec.Header().Get("Location"))
+		}
+	}
+	if stored[0].Plan != "pro" || stored[0].Seats != 3 {
+		t.Errorf("stored %+v", stored[0])
+	}
+}
+
+func TestHandlerPage(t *testing.T) {
+
+	h := NewHandler[signupFormT](nil, nil)
+	h.Page = template.Must(template.New("page").Parse(`<main data-path='{{.Request.URL.Path}}'>{{.Form}}</main>`))
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return s2f.SetOptions("plan", []string{"free"}, []string{"Free"})
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/signup", nil))
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "<main data-path='/signup'>") || !strings.Contains(body, "<form name='frmMain'") {
+		t.Errorf("page template not applied\n%v", body)
+	}
+
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return errors.New("database down")
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/signup", nil))
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "database") {
+		t.Errorf("options error: status %v - %q", rec.Code, rec.Body.String())
+	}
+
+	// not a struct
+	rec = httptest.NewRecorder()
+	NewHandler[string](nil, nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("non-struct form: status %v", rec.Code)
+	}
+}
+
+func TestHandlerGetMethodConverter(t *testing.T) {
+
+	s2f := New()
+	s2f.Method = "GET"
+	calls := 0
+	h := NewHandler(s2f, func(ctx context.Context, frm *signupFormT) error {
+		calls++
+		return nil
+	})
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return s2f.SetOptions("plan", []string{"free"}, []string{"Free"})
+	}
+
+	// a valid submission in the query is not processed
+	q := url.Values{}
+	q.Set("token", s2f.FormToken())
+	q.Set("name", "Jane")
+	for _, method := range []string{"GET", "HEAD"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/signup?"+q.Encode(), nil))
+		if rec.Code != http.StatusOK || calls != 0 {
+			t.Errorf("%v: status %v - %v OnValid calls", method, rec.Code, calls)
+		}
+		if method == "GET" && !strings.Contains(rec.Body.String(), "method='POST'") {
+			t.Errorf("%v: form must be posted", method)
+		}
+		if strings.Contains(rec.Body.String(), "value='Jane'") {
+			t.Errorf("%v: query values must not be decoded", method)
+		}
+	}
+	if s2f.Method != "GET" {
+		t.Errorf("converter must not be changed")
+	}
+}
+
+type orderFormT struct {
+	Toppings []string `json:"toppings"  form:"subtype='select',multiple='true'"`
+	tenant   string
+}
+
+func TestHandlerInitSlices(t *testing.T) {
+
+	var stored orderFormT
+	h := NewHandler(New(), func(ctx context.Context, frm *orderFormT) error {
+		stored = *frm
+		return nil
+	})
+	h.Init = func(r *http.Request) orderFormT {
+		return orderFormT{Toppings: []string{"olives"}, tenant: "acme"}
+	}
+	h.Options = func(ctx context.Context, s2f OptionsSetter) error {
+		return s2f.SetOptions("toppings", []string{"ham", "olives"}, []string{"Ham", "Olives"})
+	}
+
+	data := url.Values{}
+	data.Set("token", New().FormToken())
+	data.Set("toppings", "ham")
+	req := httptest.NewRequest("POST", "/order", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status %v - want %v", rec.Code, http.StatusSeeOther)
+	}
+	if len(stored.Toppings) != 1 || stored.Toppings[0] != "ham" {
+		t.Errorf("posted toppings appended to init values: %v", stored.Toppings)
+	}
+	if stored.tenant != "acme" {
+		t.Errorf("init value of unexported field lost: %q", stored.tenant)
+	}
+}
@@ -0,0 +1,35 @@
+package struc2frm
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler is the no-op default for s2f.Logger;
+// assign any slog.Logger to route or activate logging, i.e.
+//
+//	s2f.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (dh discardHandler) WithAttrs([]slog.Attr) slog.Handler     { return dh }
+func (dh discardHandler) WithGroup(string) slog.Handler          { return dh }
+
+// logger returns s2f.Logger with attributes form name and instance ID
+func (s2f *s2FT) logger() *slog.Logger {
+	lg := s2f.Logger
+	if lg == nil {
+		lg = slog.New(discardHandler{})
+	}
+	return lg.With("form", s2f.Name, "instance", s2f.InstanceID)
+}
+
+// pkgLogger is the logger of package funcs and package initialization -
+// without converter at hand; no-op until defaultS2F is set
+func pkgLogger() *slog.Logger {
+	if defaultS2F == nil {
+		return slog.New(discardHandler{})
+	}
+	return defaultS2F.logger()
+}
@@ -0,0 +1,39 @@
+package struc2frm
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	s2f := New()
+	s2f.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	data := url.Values{}
+	data.Set("groups", "3")
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	s2f.Decode(req, &entryForm{})
+
+	got := buf.String()
+	for _, want := range []string{
+		`"level":"WARN"`,
+		`"form":"frmMain"`,
+		`"instance":"` + s2f.InstanceID + `"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log record lacks %v - got %v", want, got)
+		}
+	}
+
+	// default is no-op
+	if New().Logger.Enabled(req.Context(), slog.LevelError) {
+		t.Errorf("default logger should be disabled")
+	}
+}
@@ -0,0 +1,70 @@
+package struc2frm
+
+import (
+	"fmt"
+	"io"
+)
+
+// Level of a message - see AddMessage()
+type Level int
+
+// Message levels; rendered with CSS classes info-block, success-block, warning-block and error-block -
+// or global-info, global-success... on top of the form
+const (
+	LevelInfo Level = iota
+	LevelSuccess
+	LevelWarning
+	LevelError
+)
+
+var levelNames = [...]string{"info", "success", "warning", "error"}
+
+func (l Level) String() string {
+	if l < LevelInfo || l > LevelError {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// message is a non-error message of AddMessage()
+type message struct {
+	level Level
+	text  string
+}
+
+// AddMessage adds msg for the input nameJSON - shown beside the input;
+// key 'global' shows msg on top of the form;
+// LevelError is AddError(); messages of other levels neither mark the input invalid
+// nor appear in the error summary.
+// Messages for the same key are joined; the highest level determines the class.
+func (s2f *s2FT) AddMessage(nameJSON string, level Level, msg string) {
+	if level >= LevelError {
+		s2f.AddError(nameJSON, msg)
+		return
+	}
+	if s2f.messages == nil {
+		s2f.messages = map[string]message{}
+	}
+	m, ok := s2f.messages[nameJSON]
+	if ok {
+		m.text += "<br>\n"
+	}
+	m.text += msg
+	m.level = max(m.level, level)
+	s2f.messages[nameJSON] = m
+}
+
+// renderGlobalMessage writes the message for key 'global' - if any
+func (s2f *s2FT) renderGlobalMessage(w io.Writer) {
+	if m, ok := s2f.messages["global"]; ok {
+		fmt.Fprintf(w, "\t<p class='global-%v' role='status' >%v</p>\n", m.level, m.text)
+	}
+}
+
+// renderMessage writes the message for input inpName - if any;
+// id is the element id of the input
+func (s2f *s2FT) renderMessage(w io.Writer, inpName, id string) {
+	if m, ok := s2f.messages[inpName]; ok {
+		fmt.Fprintf(w, "\t<p class='%v-block' id='%v_msg' >%v</p>\n", m.level, id, m.text)
+	}
+}
@@ -0,0 +1,73 @@
+package struc2frm
+
+import (
+	"strings"
+	"testing"
+)
+
+type noticeFormT struct {
+	Status string `json:"status"  form:"role='status'"`
+	Name   string `json:"name"    form:"suffix='as in passport'"`
+	Email  string `json:"email"`
+}
+
+func TestAddMessage(t *testing.T) {
+
+	s2f := New()
+	s2f.AddMessage("global", LevelInfo, "Draft saved")
+	s2f.AddMessage("global", LevelWarning, "Session ends soon")
+	s2f.AddMessage("name", LevelSuccess, "Name is available")
+	s2f.AddMessage("email", LevelError, "Invalid email")
+
+	frm := noticeFormT{Status: "Imported <3> records", Name: "Jane"}
+	html := string(s2f.Form(frm))
+	for idx, want := range []string{
+		"<p class='global-warning' role='status' >Draft saved<br>\nSession ends soon</p>",
+		"<p class='global-info' role='status' id='status' >Imported &lt;3&gt; records</p>",
+		"<p class='success-block' id='name_msg' >Name is available</p>",
+		"aria-describedby='name_msg name_sfx'",
+		"<p class='error-block' id='email_err' >Invalid email</p>",
+		"There is 1 error", // success messages are not listed
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("idx%2v: missing %q", idx, want)
+		}
+	}
+	if strings.Contains(html, "name='status'") {
+		t.Errorf("status field should not be rendered as input")
+	}
+	if strings.Contains(html, "id='name' value='Jane'  aria-invalid") {
+		t.Errorf("success message should not mark the input invalid")
+	}
+	for _, problem := range a11yProblems(html) {
+		t.Error(problem)
+	}
+
+	card := string(s2f.Card(frm))
+	for idx, want := range []string{
+		"<p class='global-warning' role='status' >",
+		"<span class='success-block' >Name is available</span>",
+	} {
+		if !strings.Contains(card, want) {
+			t.Errorf("card idx%2v: missing %q", idx, want)
+		}
+	}
+	if strings.Contains(card, "Imported") {
+		t.Errorf("status field should not be a card entry")
+	}
+
+	for idx, tt := range []struct {
+		level Level
+		want  string
+	}{
+		{LevelInfo, "info"},
+		{LevelSuccess, "success"},
+		{LevelWarning, "warning"},
+		{LevelError, "error"},
+		{Level(7), "Level(7)"},
+	} {
+		if got := tt.level.String(); got != tt.want {
+			t.Errorf("idx%2v: got %q - want %q", idx, got, tt.want)
+		}
+	}
+}
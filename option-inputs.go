@@ -0,0 +1,71 @@
+package struc2frm
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// Radio renders one <input type='radio' /> per option - for subtype radiogroup;
+// see inputs()
+func (opts options) Radio(name string, selecteds []string) string {
+	return opts.inputs("radio", name, selecteds, "")
+}
+
+// Checkboxes renders one <input type='checkbox' /> per option - for subtype checkboxgroup;
+// see inputs()
+func (opts options) Checkboxes(name string, selecteds []string) string {
+	return opts.inputs("checkbox", name, selecteds, "")
+}
+
+// inputs renders radios or checkboxes - each followed by its label;
+// ids are name plus option index - matched by the label's for;
+// keys and labels are escaped;
+// options without label are named by aria-label - their key;
+// options with Group are wrapped into <div role='group'>;
+// attrs are added to every input - i.e. onchange
+func (opts options) inputs(tp, name string, selecteds []string, attrs string) string {
+	w := &bytes.Buffer{}
+	group := ""
+	for idx, o := range opts {
+		if o.Group != group {
+			if group != "" {
+				fmt.Fprint(w, "\t\t</div>\n")
+			}
+			if o.Group != "" {
+				fmt.Fprintf(w, "\t\t<div class='%v-optgroup' role='group' aria-label='%v'><span class='%v-optgroup-label'>%v</span>\n",
+					tp, template.HTMLEscapeString(o.Group), tp, template.HTMLEscapeString(o.Group))
+			}
+			group = o.Group
+		}
+
+		checked := ""
+		for _, selected := range selecteds {
+			if o.Key == selected {
+				checked = " checked"
+			}
+		}
+
+		id := fmt.Sprintf("%v_%v", name, idx)
+		key := template.HTMLEscapeString(o.Key)
+		if o.Val == "" {
+			ariaLabel := o.Key
+			if ariaLabel == "" {
+				ariaLabel = "none"
+			}
+			fmt.Fprintf(w,
+				"\t\t<span class='%v-item'><input type='%v' name='%v' id='%v' value='%v' aria-label='%v'%v%v%v /></span>\n",
+				tp, tp, name, id, key, template.HTMLEscapeString(ariaLabel), checked, o.attrs(), attrs,
+			)
+			continue
+		}
+		fmt.Fprintf(w,
+			"\t\t<span class='%v-item'><input type='%v' name='%v' id='%v' value='%v'%v%v%v /><label for='%v' >%v</label></span>\n",
+			tp, tp, name, id, key, checked, o.attrs(), attrs, id, template.HTMLEscapeString(o.Val),
+		)
+	}
+	if group != "" {
+		fmt.Fprint(w, "\t\t</div>\n")
+	}
+	return w.String()
+}
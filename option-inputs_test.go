@@ -0,0 +1,88 @@
+package struc2frm
+
+import (
+	"strings"
+	"testing"
+)
+
+type radioFormT struct {
+	Size string `json:"size"  form:"subtype='radiogroup',onchange='true',accesskey='z'"`
+}
+
+func TestRadioGroup(t *testing.T) {
+
+	tests := []struct {
+		nonce  string
+		legend string
+		want   string
+	}{
+		{"", "<legend class='group-legend' style='' >Si<u>z</u>e</legend>", "<input type='radio' name='size' id='size_1' value='l' checked onchange='javascript:this.form.submit();' /><label for='size_1' >Large</label>"},
+		{"abc", "<legend class='group-legend' >Si<u>z</u>e</legend>", "<input type='radio' name='size' id='size_1' value='l' checked data-s2f-submit='true' /><label for='size_1' >Large</label>"},
+	}
+	for idx, tt := range tests {
+		s2f := New()
+		s2f.CSPNonce = tt.nonce
+		s2f.SetOptions("size", []string{"m", "l"}, []string{"Medium", "Large"})
+		html := string(s2f.Form(radioFormT{Size: "l"}))
+		for _, want := range []string{
+			"<fieldset class='radio-group' role='radiogroup' id='size' >",
+			tt.legend,
+			tt.want,
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("idx%2v: missing %q\n%v", idx, want, html)
+			}
+		}
+		if strings.Contains(html, "<label for='size' ") {
+			t.Errorf("idx%2v: label pointing to no input", idx)
+		}
+		// onchange submits - no submit button needed
+		if strings.Contains(html, "type='submit'") {
+			t.Errorf("idx%2v: submit button not expected", idx)
+		}
+	}
+}
+
+func TestOptionEscaping(t *testing.T) {
+
+	opts := options{
+		{Key: "o'neil", Val: "O'Neil <Ltd>"},
+		{Key: "<b>", Val: ""},
+	}
+	tests := []struct {
+		html string
+		want []string
+	}{
+		{
+			opts.HTML([]string{"o'neil"}),
+			[]string{
+				"<option value='o&#39;neil' selected >O&#39;Neil &lt;Ltd&gt;</option>",
+				"<option value='&lt;b&gt;'          ></option>",
+			},
+		},
+		{
+			opts.Radio("firm", []string{"o'neil"}),
+			[]string{
+				"value='o&#39;neil' checked /><label for='firm_0' >O&#39;Neil &lt;Ltd&gt;</label>",
+				"value='&lt;b&gt;' aria-label='&lt;b&gt;' />",
+			},
+		},
+		{
+			opts.Checkboxes("firm", nil),
+			[]string{
+				"value='o&#39;neil' /><label for='firm_0' >O&#39;Neil &lt;Ltd&gt;</label>",
+				"value='&lt;b&gt;' aria-label='&lt;b&gt;' />",
+			},
+		},
+	}
+	for idx, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(tt.html, want) {
+				t.Errorf("idx%2v: missing %q\n%v", idx, want, tt.html)
+			}
+		}
+		if strings.Contains(tt.html, "<b>") || strings.Contains(tt.html, "o'neil") {
+			t.Errorf("idx%2v: unescaped option\n%v", idx, tt.html)
+		}
+	}
+}
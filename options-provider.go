@@ -0,0 +1,70 @@
+package struc2frm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// OptionsProvider computes the options of a dependent select
+// from the values of its parent fields - declared by dependson='department';
+// parentValues are keyed by json name.
+type OptionsProvider func(ctx context.Context, parentValues map[string]string) []Option
+
+// SetOptionsProvider registers p for the select nameJSON;
+// it takes precedence over SetOptions().
+func (s2f *s2FT) SetOptionsProvider(nameJSON string, p OptionsProvider) {
+	if s2f.optionsProviders == nil {
+		s2f.optionsProviders = map[string]OptionsProvider{}
+	}
+	s2f.optionsProviders[nameJSON] = p
+}
+
+// OptionsHandler serves the options of dependent selects as JSON
+//
+//	[{"key":"ub","label":"UB"}, ...]
+//
+// for requests like ?field=items&department=ub;
+// mount it under s2f.OptionsURL - struc2frm.js then refreshes
+// dependent selects in place, instead of submitting the form.
+func (s2f *s2FT) OptionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		field := r.URL.Query().Get("field")
+		p, ok := s2f.optionsProviders[field]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		parentValues := map[string]string{}
+		for key := range r.URL.Query() {
+			if key != "field" {
+				parentValues[key] = r.URL.Query().Get(key)
+			}
+		}
+		opts := []Option(s2f.validData(field, p(r.Context(), parentValues)))
+		if opts == nil {
+			opts = []Option{} // JSON array - not null
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(opts); err != nil {
+			s2f.logger().Error("encoding options", "field", field, "err", err)
+		}
+	})
+}
+
+// dependsOnAttrs renders the data- attributes of a dependent select for struc2frm.js;
+// without OptionsURL, struc2frm.js submits the form on parent changes
+func (s2f *s2FT) dependsOnAttrs(fs *fieldSchema) string {
+	if len(fs.dependsOn) == 0 {
+		return ""
+	}
+	attrs := fmt.Sprintf(" data-s2f-dependson='%v'", template.HTMLEscapeString(strings.Join(fs.dependsOn, ",")))
+	if s2f.OptionsURL != "" {
+		attrs += fmt.Sprintf(" data-s2f-options='%v'", template.HTMLEscapeString(s2f.OptionsURL))
+	}
+	return attrs
+}
@@ -0,0 +1,107 @@
+package struc2frm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type cascadeFormT struct {
+	Department string `json:"department"  form:"subtype='select'"`
+	Employee   string `json:"employee"    form:"subtype='select',dependson='department'"`
+}
+
+func employeesProvider(ctx context.Context, parentValues map[string]string) []Option {
+	opts := []Option{}
+	for _, name := range itemGroups[parentValues["department"]] {
+		opts = append(opts, Option{Key: strings.ToLower(name[:3]), Val: name})
+	}
+	return opts
+}
+
+func TestOptionsProvider(t *testing.T) {
+
+	s2f := New()
+	s2f.OptionsURL = "/struc2frm-options"
+	s2f.SetOptions("department", []string{"ub", "fm"}, []string{"UB", "FM"})
+	s2f.SetOptionsProvider("employee", employeesProvider)
+
+	tests := []struct {
+		in   cascadeFormT
+		want string
+		not  string
+	}{
+		{
+			in:   cascadeFormT{Department: "ub"},
+			want: ">Brutsyum, Zusoh</option>",
+			not:  ">Bsackbuaos, Punk</option>",
+		},
+		{
+			in:   cascadeFormT{Department: "fm", Employee: "rac"},
+			want: "<option value='rac' selected >Rachydt, Racho</option>",
+			not:  ">Brutsyum, Zusoh</option>",
+		},
+	}
+	for idx, tt := range tests {
+		got := string(s2f.Form(tt.in))
+		if !strings.Contains(got, tt.want) || strings.Contains(got, tt.not) {
+			t.Errorf("idx%2v: want %q - not %q", idx, tt.want, tt.not)
+		}
+		for _, want := range []string{
+			"data-s2f-dependson='department' data-s2f-options='/struc2frm-options'",
+			"<noscript><button type='submit' name='btnRefresh' value='department' >",
+			"function s2fRefreshDependents",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("idx%2v: missing %q", idx, want)
+			}
+		}
+	}
+
+	// card resolves labels from the provider
+	card := string(s2f.Card(cascadeFormT{Department: "fm", Employee: "hir"}))
+	if !strings.Contains(card, "Hiroso, Meivynu") {
+		t.Errorf("card should contain the label of the provided option")
+	}
+
+	if err := s2f.Check(cascadeFormT{}); err != nil {
+		t.Errorf("Check() got %v", err)
+	}
+}
+
+func TestOptionsHandler(t *testing.T) {
+
+	s2f := New()
+	s2f.SetOptionsProvider("employee", employeesProvider)
+
+	tests := []struct {
+		url        string
+		wantStatus int
+		wantLen    int
+	}{
+		{"/struc2frm-options?field=employee&department=fm", http.StatusOK, 5},
+		{"/struc2frm-options?field=employee&department=xx", http.StatusOK, 0},
+		{"/struc2frm-options?field=department", http.StatusNotFound, 0},
+	}
+	for idx, tt := range tests {
+		rec := httptest.NewRecorder()
+		s2f.OptionsHandler().ServeHTTP(rec, httptest.NewRequest("GET", tt.url, nil))
+		if rec.Code != tt.wantStatus {
+			t.Errorf("idx%2v: status %v - want %v", idx, rec.Code, tt.wantStatus)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		opts := []Option{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &opts); err != nil {
+			t.Errorf("idx%2v: invalid JSON %q: %v", idx, rec.Body.String(), err)
+		}
+		if len(opts) != tt.wantLen {
+			t.Errorf("idx%2v: %v options - want %v", idx, len(opts), tt.wantLen)
+		}
+	}
+}
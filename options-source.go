@@ -0,0 +1,204 @@
+package struc2frm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// OptionsSource supplies the options of a select or radiogroup;
+// resolved at each rendering - i.e. from a database with the request context.
+type OptionsSource interface {
+	Options(ctx context.Context) ([]Option, error)
+}
+
+// OptionsSourceFunc adapts a func to OptionsSource
+type OptionsSourceFunc func(ctx context.Context) ([]Option, error)
+
+// Options calls f
+func (f OptionsSourceFunc) Options(ctx context.Context) ([]Option, error) {
+	return f(ctx)
+}
+
+// FormOptioner is implemented by enum types listing their values, i.e.
+//
+//	type Fruit string
+//	func (Fruit) FormOptions() []struc2frm.Option { ... }
+type FormOptioner interface {
+	FormOptions() []Option
+}
+
+// SetOptionsSource registers the options of select or radiogroup nameJSON;
+// src is one of
+//
+//	OptionsSource or OptionsSourceFunc - resolved at rendering
+//	FormOptioner                       - an enum type; resolved at rendering
+//	[]Option
+//	a slice of structs with fields Key and Label - i.e. []struct{Key, Label string};
+//	                                     optional fields Group and Disabled are taken over
+//	a map                              - ordered by key
+//
+// Returns an error for other types.
+// Takes precedence over SetOptions(); OptionsProvider takes precedence over src.
+func (s2f *s2FT) SetOptionsSource(nameJSON string, src interface{}) error {
+	source, err := toOptionsSource(src)
+	if err != nil {
+		return fmt.Errorf("struc2frm: SetOptionsSource(%v): %w", nameJSON, err)
+	}
+	if s2f.optionsSources == nil {
+		s2f.optionsSources = map[string]OptionsSource{}
+	}
+	s2f.optionsSources[nameJSON] = source
+	return nil
+}
+
+// staticOptions is the OptionsSource for slices and maps - converted once
+type staticOptions []Option
+
+func (so staticOptions) Options(ctx context.Context) ([]Option, error) {
+	return so, nil
+}
+
+func toOptionsSource(src interface{}) (OptionsSource, error) {
+
+	switch s := src.(type) {
+	case OptionsSource:
+		return s, nil
+	case func(ctx context.Context) ([]Option, error):
+		return OptionsSourceFunc(s), nil
+	case FormOptioner:
+		return OptionsSourceFunc(func(context.Context) ([]Option, error) {
+			return s.FormOptions(), nil
+		}), nil
+	case []Option:
+		return staticOptions(s), nil
+	}
+
+	v := reflect.ValueOf(src)
+	switch v.Kind() {
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unsupported options type %T - slice elements must be structs", src)
+		}
+		_, hasKey := v.Type().Elem().FieldByName("Key")
+		_, hasLabel := v.Type().Elem().FieldByName("Label")
+		if !hasKey || !hasLabel {
+			return nil, fmt.Errorf("unsupported options type %T - struct needs fields Key and Label", src)
+		}
+		so := make(staticOptions, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			el := v.Index(i)
+			opt := Option{
+				Key: fmt.Sprint(el.FieldByName("Key").Interface()),
+				Val: fmt.Sprint(el.FieldByName("Label").Interface()),
+			}
+			if grp := el.FieldByName("Group"); grp.IsValid() {
+				opt.Group = fmt.Sprint(grp.Interface())
+			}
+			if dis := el.FieldByName("Disabled"); dis.IsValid() && dis.Kind() == reflect.Bool {
+				opt.Disabled = dis.Bool()
+			}
+			so = append(so, opt)
+		}
+		return so, nil
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessValue(keys[i], keys[j])
+		})
+		so := make(staticOptions, 0, len(keys))
+		for _, key := range keys {
+			so = append(so, Option{
+				Key: fmt.Sprint(key.Interface()),
+				Val: fmt.Sprint(v.MapIndex(key).Interface()),
+			})
+		}
+		return so, nil
+	}
+
+	return nil, fmt.Errorf("unsupported options type %T", src)
+}
+
+// lessValue orders map keys - numerically for numbers
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// hasOptions is true, if options were set or a source or provider was registered
+func (s2f *s2FT) hasOptions(nameJSON string) bool {
+	_, hasProvider := s2f.optionsProviders[nameJSON]
+	_, hasSource := s2f.optionsSources[nameJSON]
+	return hasProvider || hasSource || len(s2f.selectOptions[nameJSON]) > 0
+}
+
+// optionsOf returns the options of field fs;
+// from its provider - given the parent values of struct v -
+// from its source, or from SetOptions()
+func (s2f *s2FT) optionsOf(ctx context.Context, v reflect.Value, sch *formSchema, fs *fieldSchema) (options, error) {
+
+	if p, ok := s2f.optionsProviders[fs.inpName]; ok {
+		parentValues := map[string]string{}
+		for _, parent := range fs.dependsOn {
+			if pfs := sch.byInpName(parent); pfs != nil && pfs.exported {
+				parentValues[parent] = ValToString(v.Field(pfs.index))
+			}
+		}
+		return s2f.validData(fs.inpName, p(ctx, parentValues)), nil
+	}
+
+	if src, ok := s2f.optionsSources[fs.inpName]; ok {
+		opts, err := src.Options(ctx)
+		if err != nil {
+			return nil, &FieldError{Field: fs.name, Err: err}
+		}
+		return s2f.validData(fs.inpName, opts), nil
+	}
+
+	return s2f.validData(fs.inpName, s2f.selectOptions[fs.inpName]), nil
+}
+
+// resolveOptions resolves the options of all rendered fields of struct v;
+// before anything is written - so that source errors can be returned
+func (s2f *s2FT) resolveOptions(ctx context.Context, v reflect.Value, sch *formSchema) (map[string]options, error) {
+	resolved := map[string]options{}
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if fs.exported && !fs.skip && fs.inputType == "repeat" {
+			for _, sub := range rowFields(fs) {
+				// selects inside rows by dotted name; i.e. items.unit
+				rowFs := *sub
+				rowFs.inpName = rowOptionsName(fs.inpName, sub.inpName)
+				rowFs.name = fs.name + "." + sub.name
+				rowFs.dependsOn = nil
+				if !s2f.hasOptions(rowFs.inpName) {
+					continue
+				}
+				opts, err := s2f.optionsOf(ctx, v, sch, &rowFs)
+				if err != nil {
+					return nil, err
+				}
+				resolved[rowFs.inpName] = opts
+			}
+		}
+		if !fs.exported || fs.skip || !s2f.hasOptions(fs.inpName) {
+			continue
+		}
+		opts, err := s2f.optionsOf(ctx, v, sch, fs)
+		if err != nil {
+			return nil, err
+		}
+		resolved[fs.inpName] = opts
+	}
+	return resolved, nil
+}
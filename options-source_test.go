@@ -0,0 +1,181 @@
+package struc2frm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type fruitT string
+
+func (fruitT) FormOptions() []Option {
+	return []Option{{Key: "pear", Val: "Pear"}, {Key: "plum", Val: "Plum"}}
+}
+
+type sourceFormT struct {
+	Fruit string `json:"fruit"  form:"subtype='select'"`
+}
+
+func TestOptionsSource(t *testing.T) {
+
+	errDB := errors.New("database down")
+
+	tests := []struct {
+		src     interface{}
+		want    []string // option keys in order
+		wantErr bool     // at SetOptionsSource()
+		wantRnd error    // at RenderForm()
+	}{
+		{src: []Option{{Key: "a", Val: "A"}, {Key: "b", Val: "B"}}, want: []string{"a", "b"}},
+		{src: []struct{ Key, Label string }{{"x", "X"}, {"y", "Y"}}, want: []string{"x", "y"}},
+		{src: []struct {
+			Key   int
+			Label string
+		}{{2, "two"}, {1, "one"}}, want: []string{"2", "1"}},
+		{src: map[string]string{"c": "C", "a": "A", "b": "B"}, want: []string{"a", "b", "c"}},
+		{src: map[int]string{10: "ten", 9: "nine", 100: "hundred"}, want: []string{"9", "10", "100"}},
+		{src: fruitT(""), want: []string{"pear", "plum"}},
+		{src: OptionsSourceFunc(func(ctx context.Context) ([]Option, error) {
+			return []Option{{Key: "ctx", Val: "from context"}}, nil
+		}), want: []string{"ctx"}},
+		{src: OptionsSourceFunc(func(ctx context.Context) ([]Option, error) {
+			return nil, errDB
+		}), wantRnd: errDB},
+		{src: []string{"a", "b"}, wantErr: true},
+		{src: []struct{ Key, Val string }{{"x", "X"}}, wantErr: true},
+		{src: 42, wantErr: true},
+	}
+
+	for idx, tt := range tests {
+
+		s2f := New()
+		err := s2f.SetOptionsSource("fruit", tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("idx%2v: SetOptionsSource() error %v - want %v", idx, err, tt.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		w := &strings.Builder{}
+		err = s2f.RenderForm(w, sourceFormT{})
+		if !errors.Is(err, tt.wantRnd) {
+			t.Errorf("idx%2v: RenderForm() error %v - want %v", idx, err, tt.wantRnd)
+		}
+		if err != nil {
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != "Fruit" || w.Len() > 0 {
+				t.Errorf("idx%2v: source error should be *FieldError before writing - got %v", idx, err)
+			}
+			continue
+		}
+
+		got := w.String()
+		pos := 0
+		for _, key := range tt.want {
+			p := strings.Index(got, "<option value='"+key+"'")
+			if p < pos {
+				t.Errorf("idx%2v: option %v missing or out of order", idx, key)
+			}
+			pos = p
+		}
+		if s2f.DefaultOptionKey("fruit") != tt.want[0] {
+			t.Errorf("idx%2v: DefaultOptionKey() got %v - want %v", idx, s2f.DefaultOptionKey("fruit"), tt.want[0])
+		}
+	}
+}
+
+func TestSetOptionsMismatch(t *testing.T) {
+	s2f := New()
+	err := s2f.SetOptions("fruit", []string{"a", "b"}, []string{"A"})
+	if !errors.Is(err, ErrOptionsMismatch) {
+		t.Errorf("want ErrOptionsMismatch - got %v", err)
+	}
+	if strings.Contains(string(s2f.Form(sourceFormT{})), "does not match") {
+		t.Errorf("no fake option should be rendered")
+	}
+	if err := s2f.Check(sourceFormT{}); err == nil {
+		t.Errorf("Check() should report missing options")
+	}
+}
+
+func TestOptionGroups(t *testing.T) {
+
+	opts := options{
+		{Key: "", Val: "Please choose"},
+		{Key: "de", Val: "Germany", Group: "Europe", Data: map[string]string{"iso3": "DEU", "eu": "true"}},
+		{Key: "fr", Val: "France", Group: "Europe"},
+		{Key: "jp", Val: "Japan", Group: "Asia", Disabled: true, Title: "not 'shipping'"},
+	}
+
+	html := opts.HTML([]string{"fr"})
+	tests := []string{
+		"<option value=''          >Please choose</option>\n\t\t<optgroup label='Europe'>",
+		"<option value='de' data-eu='true' data-iso3='DEU'          >Germany</option>",
+		"<option value='fr' selected >France</option>\n\t\t</optgroup>\n\t\t<optgroup label='Asia'>",
+		"<option value='jp' disabled title='not &#39;shipping&#39;'          >Japan</option>\n\t\t</optgroup>\n",
+	}
+	for idx, want := range tests {
+		if !strings.Contains(html, want) {
+			t.Errorf("idx%2v: HTML() missing %q\n%v", idx, want, html)
+		}
+	}
+
+	radio := opts.Radio("country", []string{"de"})
+	tests = []string{
+		"<div class='radio-optgroup' role='group' aria-label='Europe'><span class='radio-optgroup-label'>Europe</span>",
+		"<input type='radio' name='country' id='country_1' value='de' checked data-eu='true' data-iso3='DEU' /><label for='country_1' >Germany</label>",
+		"<input type='radio' name='country' id='country_3' value='jp' disabled title='not &#39;shipping&#39;' /><label for='country_3' >Japan</label></span>\n\t\t</div>\n",
+	}
+	for idx, want := range tests {
+		if !strings.Contains(radio, want) {
+			t.Errorf("idx%2v: Radio() missing %q\n%v", idx, want, radio)
+		}
+	}
+	if strings.Count(radio, "<div") != strings.Count(radio, "</div>") {
+		t.Errorf("Radio() unbalanced groups\n%v", radio)
+	}
+
+	// card shows the group
+	s2f := New()
+	s2f.SetOptionsSource("fruit", []struct {
+		Key, Label, Group string
+	}{{"pear", "Pear", "Pome"}, {"plum", "Plum", "Stone fruit"}})
+	card := string(s2f.Card(sourceFormT{Fruit: "plum"}))
+	if !strings.Contains(card, "Stone fruit - Plum") {
+		t.Errorf("Card() should show the grouped label\n%v", card)
+	}
+}
+
+func TestOptionDataKeys(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	s2f := New()
+	s2f.Logger = slog.New(slog.NewJSONHandler(buf, nil))
+	data := map[string]string{"iso3": "DEU", "x onmouseover": "alert(1)", "Upper": "u", "-lead": "l", "eu-member": "true"}
+	s2f.selectOptions["fruit"] = options{{Key: "pear", Val: "Pear", Data: data}}
+
+	html := string(s2f.Form(sourceFormT{}))
+	if !strings.Contains(html, "<option value='pear' data-eu-member='true' data-iso3='DEU'          >Pear</option>") {
+		t.Errorf("valid data keys missing\n%v", html)
+	}
+	for _, bad := range []string{"onmouseover", "data-Upper", "data--lead"} {
+		if strings.Contains(html, bad) {
+			t.Errorf("invalid data key rendered: %v", bad)
+		}
+	}
+	if got := strings.Count(buf.String(), "option data key dropped"); got != 3 {
+		t.Errorf("want 3 dropped keys logged - got %v\n%v", got, buf.String())
+	}
+	if len(s2f.selectOptions["fruit"][0].Data) != 5 {
+		t.Errorf("shared options must not be changed")
+	}
+
+	// rendered directly - without resolving
+	if attrs := (Option{Data: data}).attrs(); attrs != " data-eu-member='true' data-iso3='DEU'" {
+		t.Errorf("attrs() got %q", attrs)
+	}
+}
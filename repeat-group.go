@@ -0,0 +1,307 @@
+package struc2frm
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// rowInputTypes are the input types rendered inside the rows of a repeatable group
+var rowInputTypes = map[string]bool{
+	"text": true, "number": true, "date": true, "time": true,
+	"textarea": true, "checkbox": true, "select": true,
+}
+
+// rowSchema returns the schema of the row struct of a repeatable group
+func (fs *fieldSchema) rowSchema() *formSchema {
+	return schemaOf(fs.elem)
+}
+
+// rowFields returns the fields rendered inside the rows of repeatable group fs
+func rowFields(fs *fieldSchema) []*fieldSchema {
+	subs := []*fieldSchema{}
+	rowSch := fs.rowSchema()
+	for i := range rowSch.fields {
+		sub := &rowSch.fields[i]
+		if sub.exported && !sub.skip && rowInputTypes[sub.inputType] {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// rowInpName returns the input name of field sub in row idx;
+// i.e. items[0].qty - as decoded by go-playground/form
+func rowInpName(group, idx, sub string) string {
+	return fmt.Sprintf("%v[%v].%v", group, idx, sub)
+}
+
+// rowID returns the element id of field sub in row idx; i.e. items_0_qty
+func rowID(group, idx, sub string) string {
+	return fmt.Sprintf("%v_%v_%v", group, idx, sub)
+}
+
+// rowOptionsName returns the name for SetOptions() of a select inside rows; i.e. items.unit
+func rowOptionsName(group, sub string) string {
+	return group + "." + sub
+}
+
+// rowCounts returns the 'form' tags min, max and blank-rows of a repeatable group;
+// blank-rows defaults to 1
+func rowCounts(fs *fieldSchema) (minRows, maxRows, blankRows int) {
+	minRows, _ = strconv.Atoi(fs.tag("min"))
+	maxRows, _ = strconv.Atoi(fs.tag("max"))
+	blankRows = 1
+	if s, ok := fs.parsed.get("blank-rows"); ok {
+		blankRows, _ = strconv.Atoi(s)
+	}
+	return
+}
+
+// renderRepeat writes a slice of structs as repeatable group;
+// the existing rows are padded with blank rows up to min - plus blank-rows -
+// for browsers without JavaScript; at most max rows.
+// struc2frm.js adds rows from the template row and removes rows,
+// renumbering the input names.
+func (s2f *s2FT) renderRepeat(w io.Writer, fs *fieldSchema, val reflect.Value, legend string, resolved map[string]options) {
+
+	minRows, maxRows, blankRows := rowCounts(fs)
+	cnt := val.Len()
+	if cnt < minRows {
+		cnt = minRows
+	}
+	cnt += blankRows
+	if maxRows > 0 && cnt > maxRows {
+		cnt = max(maxRows, val.Len())
+	}
+
+	fmt.Fprintf(w, "\t<fieldset class='s2f-repeat' id='%v' data-s2f-repeat='%v' data-s2f-min='%v' data-s2f-max='%v'%v >\n",
+		fs.inpName, fs.inpName, minRows, maxRows, s2f.ariaAttrs(fs),
+	)
+	fmt.Fprint(w, legend)
+	for i := 0; i < cnt; i++ {
+		if i < val.Len() {
+			s2f.renderRow(w, fs, val.Index(i), strconv.Itoa(i), "", false, resolved)
+			continue
+		}
+		blank := ""
+		if i >= minRows {
+			blank = " data-s2f-blank" // removed by struc2frm.js - which offers the add button instead
+		}
+		s2f.renderRow(w, fs, reflect.Zero(fs.elem), strconv.Itoa(i), blank, true, resolved)
+	}
+	fmt.Fprint(w, "\t<template data-s2f-row-template >\n")
+	s2f.renderRow(w, fs, reflect.Zero(fs.elem), "__i__", "", true, resolved)
+	fmt.Fprint(w, "\t</template>\n")
+	// buttons are shown by struc2frm.js
+	fmt.Fprint(w, "\t<button type='button' class='s2f-add-row' data-s2f-add hidden >Add row</button>\n")
+	fmt.Fprint(w, "\t</fieldset>")
+}
+
+// renderRow writes the inputs of one row; idx is the row index - or __i__ for the template row;
+// padded rows - blank and template rows - get an empty leading option in selects,
+// so that untouched rows decode as zero and are dropped by dropBlankRows()
+func (s2f *s2FT) renderRow(w io.Writer, fs *fieldSchema, row reflect.Value, idx, extra string, padded bool, resolved map[string]options) {
+
+	fmt.Fprintf(w, "\t<div class='s2f-repeat-row' data-s2f-row%v >\n", extra)
+
+	for _, sub := range rowFields(fs) {
+
+		inpName := rowInpName(fs.inpName, idx, sub.inpName)
+		id := rowID(fs.inpName, idx, sub.inpName)
+		val := row.Field(sub.index)
+		valStr := template.HTMLEscapeString(ValToString(val))
+
+		if errMsg, hasError := s2f.errors[inpName]; hasError {
+			fmt.Fprintf(w, "\t\t<p class='error-block' id='%v_err' >%v</p>\n", id, errMsg)
+		}
+		s2f.renderMessage(w, inpName, id)
+
+		attrs := s2f.structTagsToAttrs(sub.parsed) + s2f.ariaAttrsOf(sub, inpName, id)
+
+		labelAttr := fmt.Sprintf("style='%v'", sub.tag("label-style"))
+		if s2f.CSPNonce != "" { // class - rule by renderCSPStyles()
+			labelAttr = "class=''"
+			if sub.tag("label-style") != "" {
+				labelAttr = fmt.Sprintf("class='%v'", labelStyleClass(fs.index, sub.index))
+			}
+		}
+		fmt.Fprintf(w, "\t\t<label for='%v' %v >%v</label>", id, labelAttr, sub.label)
+
+		switch sub.inputType {
+		case "checkbox":
+			checked := ""
+			if val.Bool() {
+				checked = "checked"
+			}
+			fmt.Fprintf(w, "<input type='checkbox' name='%v' id='%v' value='true' %v %v />", inpName, id, checked, attrs)
+			fmt.Fprintf(w, "<input type='hidden' name='%v' value='false' />", inpName)
+		case "textarea":
+			fmt.Fprintf(w, "<textarea name='%v' id='%v' %v >%v</textarea>", inpName, id, attrs, valStr)
+		case "select":
+			fmt.Fprintf(w, "<select name='%v' id='%v' %v >\n", inpName, id, attrs)
+			opts := resolved[rowOptionsName(fs.inpName, sub.inpName)]
+			selected := ValToString(val)
+			if padded {
+				opts = opts.withEmpty()
+				selected = ""
+			}
+			fmt.Fprint(w, opts.HTML([]string{selected}))
+			fmt.Fprint(w, "\t\t</select>")
+		default:
+			fmt.Fprintf(w, "<input type='%v' name='%v' id='%v' value='%v' %v />", sub.inputType, inpName, id, valStr, attrs)
+		}
+
+		if sfx := sub.tag("suffix"); sfx != "" {
+			fmt.Fprintf(w, "<span class='postlabel' id='%v_sfx' >%s</span>", id, sfx)
+		}
+		if title := sub.tag("title"); title != "" {
+			fmt.Fprintf(w, "<span class='s2f-sr-only' id='%v_title' >%v</span>", id, template.HTMLEscapeString(title))
+		}
+		fmt.Fprint(w, "\n")
+	}
+
+	fmt.Fprint(w, "\t\t<button type='button' class='s2f-remove-row' data-s2f-remove hidden >Remove row</button>\n")
+	fmt.Fprint(w, "\t</div>\n")
+}
+
+// withEmpty prepends an empty option - unless there is one already
+func (opts options) withEmpty() options {
+	for _, o := range opts {
+		if o.Key == "" {
+			return opts
+		}
+	}
+	return append(options{{Key: "", Val: ""}}, opts...)
+}
+
+// rowCountError checks the 'form' tags min and max
+// of a repeatable group against the number of rows;
+// returns the error message or ""
+func rowCountError(v reflect.Value, fs *fieldSchema) string {
+
+	if fs.inputType != "repeat" {
+		return ""
+	}
+
+	minRows, maxRows, _ := rowCounts(fs)
+	cnt := v.Field(fs.index).Len()
+	switch {
+	case cnt < minRows && minRows == 1:
+		return "Please add at least one row"
+	case cnt < minRows:
+		return fmt.Sprintf("Please add at least %v rows", minRows)
+	case maxRows > 0 && cnt > maxRows:
+		return fmt.Sprintf("Please enter at most %v rows", maxRows)
+	}
+	return ""
+}
+
+// rowErrors calls Validate() of each row - if the row struct implements Validator;
+// keys are prefixed with the row; i.e. items[0].qty
+func rowErrors(v reflect.Value, fs *fieldSchema) map[string]string {
+	errs := map[string]string{}
+	rows := v.Field(fs.index)
+	for i := 0; i < rows.Len(); i++ {
+		vldr, ok := rows.Index(i).Interface().(Validator)
+		if !ok {
+			return errs
+		}
+		rowErrs, _ := vldr.Validate()
+		for key, msg := range rowErrs {
+			errs[rowInpName(fs.inpName, strconv.Itoa(i), key)] = msg
+		}
+	}
+	return errs
+}
+
+// resetRows empties the repeatable groups of *ptr2Struct before decoding;
+// the decoder would merge the posted rows into prefilled rows -
+// bringing back rows removed in the browser
+func resetRows(ptr2Struct interface{}) {
+
+	v := reflect.ValueOf(ptr2Struct)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+
+	sch := schemaOf(v.Type())
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip || fs.inputType != "repeat" {
+			continue
+		}
+		rows := v.Field(fs.index)
+		rows.Set(reflect.MakeSlice(rows.Type(), 0, 0))
+	}
+}
+
+// dropBlankRows removes rows with only zero values
+// from the repeatable groups of *ptr2Struct;
+// these are the blank rows rendered for browsers without JavaScript
+func dropBlankRows(ptr2Struct interface{}) {
+
+	v := reflect.ValueOf(ptr2Struct)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	v = v.Elem()
+
+	sch := schemaOf(v.Type())
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip || fs.inputType != "repeat" {
+			continue
+		}
+		rows := v.Field(fs.index)
+		kept := reflect.MakeSlice(rows.Type(), 0, rows.Len())
+		for i := 0; i < rows.Len(); i++ {
+			if !rows.Index(i).IsZero() {
+				kept = reflect.Append(kept, rows.Index(i))
+			}
+		}
+		rows.Set(kept)
+	}
+}
+
+// checkRepeat validates the tags of a repeatable group and of its row fields
+func (s2f *s2FT) checkRepeat(fs *fieldSchema) []error {
+
+	errs := []error{}
+	for _, key := range []string{"min", "max", "blank-rows"} {
+		if val, ok := fs.parsed.get(key); ok {
+			if _, err := strconv.Atoi(val); err != nil {
+				errs = append(errs, &FieldError{Field: fs.name, Err: fmt.Errorf("tag 'form': %v='%v' must be an integer", key, val)})
+			}
+		}
+	}
+
+	rowSch := fs.rowSchema()
+	for i := range rowSch.fields {
+		sub := &rowSch.fields[i]
+		if !sub.exported {
+			continue
+		}
+		subName := fs.name + "." + sub.name
+		for _, err := range CheckField(sub.tp, fs.elem.Field(sub.index).Tag.Get("json"), sub.attrs) {
+			errs = append(errs, &FieldError{Field: subName, Err: err})
+		}
+		if sub.skip {
+			continue
+		}
+		if !rowInputTypes[sub.inputType] {
+			errs = append(errs, &FieldError{Field: subName, Err: fmt.Errorf("%v is not supported inside repeatable groups", sub.inputType)})
+		}
+		if sub.inputType == "select" && !s2f.hasOptions(rowOptionsName(fs.inpName, sub.inpName)) {
+			errs = append(errs, &FieldError{Field: subName, Err: fmt.Errorf("no options for select - use SetOptions(%q, ...)", rowOptionsName(fs.inpName, sub.inpName))})
+		}
+		if strings.ContainsAny(sub.inpName, "[].") {
+			errs = append(errs, &FieldError{Field: subName, Err: fmt.Errorf("json name '%v' must not contain [ ] or .", sub.inpName)})
+		}
+	}
+	return errs
+}
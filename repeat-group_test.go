@@ -0,0 +1,272 @@
+package struc2frm
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type lineItemT struct {
+	Product string  `json:"product"`
+	Qty     int     `json:"qty"      form:"min='1'"`
+	Unit    string  `json:"unit"     form:"subtype='select'"`
+	Price   float64 `json:"price"    form:"suffix='€'"`
+}
+
+func (li lineItemT) Validate() (map[string]string, bool) {
+	errs := map[string]string{}
+	if li.Qty < 1 {
+		errs["qty"] = "Quantity must be positive"
+	}
+	return errs, len(errs) == 0
+}
+
+type invoiceFormT struct {
+	Customer string      `json:"customer"`
+	Items    []lineItemT `json:"items"     form:"min='1',max='3',blank-rows='2'"`
+}
+
+func invoiceConverter() *s2FT {
+	s2f := New()
+	s2f.SetOptions("items.unit", []string{"pc", "kg"}, []string{"Piece", "Kilogram"})
+	return s2f
+}
+
+func TestRepeatGroup(t *testing.T) {
+
+	s2f := invoiceConverter()
+
+	tests := []struct {
+		in   invoiceFormT
+		rows int // rendered rows - without the template row
+		want []string
+		not  []string
+	}{
+		{
+			in:   invoiceFormT{},
+			rows: 3, // min 1 plus 2 blank rows
+			want: []string{
+				"<fieldset class='s2f-repeat' id='items' data-s2f-repeat='items' data-s2f-min='1' data-s2f-max='3' >",
+				"<div class='s2f-repeat-row' data-s2f-row >", // min row is not removed by struc2frm.js
+				"<div class='s2f-repeat-row' data-s2f-row data-s2f-blank >",
+				"<label for='items_0_product' style='' >Product</label><input type='text' name='items[0].product' id='items_0_product' value=''  />",
+				"<input type='number' name='items[__i__].qty' id='items___i___qty' value='0'  min='1' />",
+				"<span class='postlabel' id='items_2_price_sfx' >€</span>",
+				"<button type='button' class='s2f-add-row' data-s2f-add hidden >Add row</button>",
+				"function s2fRenumberRows",
+			},
+		},
+		{
+			in: invoiceFormT{Items: []lineItemT{
+				{Product: "Nails <small>", Qty: 200, Unit: "pc"},
+				{Product: "Sand", Qty: 2, Unit: "kg"},
+			}},
+			rows: 3, // capped by max
+			want: []string{
+				"name='items[0].product' id='items_0_product' value='Nails &lt;small&gt;'",
+				"<option value='kg' selected >Kilogram</option>",
+				"name='items[2].product' id='items_2_product' value=''",
+			},
+			not: []string{"items[3]"},
+		},
+	}
+
+	for idx, tt := range tests {
+		html := string(s2f.Form(tt.in))
+		if cnt := strings.Count(html, "class='s2f-repeat-row'") - 1; cnt != tt.rows {
+			t.Errorf("idx%2v: %v rows - want %v", idx, cnt, tt.rows)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(html, want) {
+				t.Errorf("idx%2v: missing %q", idx, want)
+			}
+		}
+		for _, not := range tt.not {
+			if strings.Contains(html, not) {
+				t.Errorf("idx%2v: unexpected %q", idx, not)
+			}
+		}
+		for _, problem := range a11yProblems(html) {
+			t.Errorf("idx%2v: %v", idx, problem)
+		}
+	}
+
+	if err := s2f.Check(invoiceFormT{}); err != nil {
+		t.Errorf("Check() got %v", err)
+	}
+	if err := New().Check(invoiceFormT{}); err == nil || !strings.Contains(err.Error(), `SetOptions("items.unit", ...)`) {
+		t.Errorf("Check() should require options for items.unit - got %v", err)
+	}
+
+	card := string(s2f.Card(invoiceFormT{Items: []lineItemT{{Product: "Sand", Qty: 2, Unit: "kg"}}}))
+	if !strings.Contains(card, "Product: Sand, Qty: 2, Unit: Kilogram, Price: 0") {
+		t.Errorf("card should list the rows\n%v", card)
+	}
+}
+
+func TestRepeatGroupDecode(t *testing.T) {
+
+	s2f := invoiceConverter()
+
+	tests := []struct {
+		prefill  []lineItemT // rows of the struct decoded into
+		rows     map[string]string
+		wantRows int
+		wantErrs map[string]string
+	}{
+		{
+			rows: map[string]string{
+				"items[0].product": "Nails", "items[0].qty": "200", "items[0].unit": "pc",
+				"items[1].product": "", "items[1].qty": "", "items[1].unit": "", // blank row
+			},
+			wantRows: 1,
+			wantErrs: map[string]string{},
+		},
+		{
+			rows: map[string]string{
+				"items[0].product": "", "items[0].qty": "",
+			},
+			wantRows: 0,
+			wantErrs: map[string]string{"items": "Please add at least one row"},
+		},
+		{
+			rows: map[string]string{
+				"items[0].product": "Nails", "items[0].qty": "0",
+				"items[1].product": "Sand", "items[1].qty": "2",
+			},
+			wantRows: 2,
+			wantErrs: map[string]string{"items[0].qty": "Quantity must be positive"},
+		},
+		{
+			prefill: []lineItemT{{Product: "Nails", Qty: 200}, {Product: "Screws", Qty: 50}, {Product: "Sand", Qty: 2}},
+			rows: map[string]string{ // screws removed in the browser - rows renumbered
+				"items[0].product": "Nails", "items[0].qty": "200",
+				"items[1].product": "Sand", "items[1].qty": "2",
+			},
+			wantRows: 2,
+			wantErrs: map[string]string{},
+		},
+	}
+
+	for idx, tt := range tests {
+
+		data := url.Values{}
+		data.Set("token", s2f.FormToken())
+		for key, val := range tt.rows {
+			data.Set(key, val)
+		}
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		frm := invoiceFormT{Items: tt.prefill}
+		populated, err := s2f.Decode(req, &frm)
+		if !populated || err != nil {
+			t.Errorf("idx%2v: decode %v %v", idx, populated, err)
+			continue
+		}
+		if len(frm.Items) != tt.wantRows || tt.prefill != nil && frm.Items[1].Product != "Sand" {
+			t.Errorf("idx%2v: decoded %v rows - want %v: %+v", idx, len(frm.Items), tt.wantRows, frm.Items)
+		}
+
+		errs, valid := s2f.Validate(frm)
+		if valid != (len(tt.wantErrs) == 0) || len(errs) != len(tt.wantErrs) {
+			t.Errorf("idx%2v: Validate() got %v %v", idx, errs, valid)
+		}
+		for key, msg := range tt.wantErrs {
+			if errs[key] != msg {
+				t.Errorf("idx%2v: %v got %q - want %q", idx, key, errs[key], msg)
+			}
+		}
+
+		// row errors are rendered beside the row input and in the summary
+		s2f.AddErrors(errs)
+		html := string(s2f.Form(frm))
+		if _, ok := tt.wantErrs["items[0].qty"]; ok {
+			for _, want := range []string{
+				"<p class='error-block' id='items_0_qty_err' >Quantity must be positive</p>",
+				"<a href='#items_0_qty' >Items 1 - Qty: Quantity must be positive</a>",
+			} {
+				if !strings.Contains(html, want) {
+					t.Errorf("idx%2v: missing %q", idx, want)
+				}
+			}
+		}
+		for _, problem := range a11yProblems(html) {
+			t.Errorf("idx%2v: %v", idx, problem)
+		}
+		s2f = invoiceConverter()
+	}
+}
+
+// postedOption returns the value a browser posts for an untouched select:
+// the selected option - or the first one
+func postedOption(html, name string) string {
+	start := strings.Index(html, "<select name='"+name+"'")
+	if start < 0 {
+		return "missing"
+	}
+	sel := html[start:]
+	sel = sel[:strings.Index(sel, "</select>")]
+	value := func(opt string) string {
+		opt = opt[strings.Index(opt, "value='")+len("value='"):]
+		return opt[:strings.Index(opt, "'")]
+	}
+	opts := strings.Split(sel, "<option ")[1:]
+	for _, opt := range opts {
+		if strings.Contains(opt[:strings.Index(opt, ">")], " selected ") {
+			return value(opt)
+		}
+	}
+	return value(opts[0])
+}
+
+func TestRepeatGroupBlankRowSelect(t *testing.T) {
+
+	s2f := invoiceConverter()
+	html := string(s2f.Form(invoiceFormT{Items: []lineItemT{{Product: "Nails", Qty: 200, Unit: "kg"}}}))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"items[0].unit", "kg"},   // existing row
+		{"items[1].unit", ""},     // blank row
+		{"items[__i__].unit", ""}, // template row
+	}
+	for idx, tt := range tests {
+		if got := postedOption(html, tt.name); got != tt.want {
+			t.Errorf("idx%2v: %v posts %q - want %q", idx, tt.name, got, tt.want)
+		}
+	}
+
+	// posting the untouched blank row - as a browser without JavaScript
+	data := url.Values{}
+	data.Set("token", s2f.FormToken())
+	for _, row := range []string{"0", "1"} {
+		for _, sub := range []string{"product", "qty", "unit", "price"} {
+			name := "items[" + row + "]." + sub
+			start := strings.Index(html, "name='"+name+"'")
+			switch {
+			case sub == "unit":
+				data.Set(name, postedOption(html, name))
+			case start >= 0:
+				val := html[start:]
+				val = val[strings.Index(val, "value='")+len("value='"):]
+				data.Set(name, val[:strings.Index(val, "'")])
+			}
+		}
+	}
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	frm := invoiceFormT{}
+	if _, err := s2f.Decode(req, &frm); err != nil {
+		t.Fatal(err)
+	}
+	if len(frm.Items) != 1 {
+		t.Errorf("blank row with select should be dropped - got %+v", frm.Items)
+	}
+	if errs, _ := s2f.Validate(frm); len(errs) > 0 {
+		t.Errorf("phantom row validated: %v", errs)
+	}
+}
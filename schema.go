@@ -0,0 +1,136 @@
+package struc2frm
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldSchema holds the reflected metadata of one struct field
+type fieldSchema struct {
+	index    int    // for reflect.Value.Field(index)
+	name     string // struct field name; i.e. DateLayout
+	exported bool
+
+	inpName string // json name; i.e. date_layout
+	label   string // labelized json name - or label from the 'form' tag
+	attrs   string // the 'form' tag; i.e. "maxlength='42',size='28'"
+	skip    bool   // form:"-"
+
+	parsed formTag // the 'form' tag tokenized
+	tagErr error   // syntax error in the 'form' tag - or in its showif/hideif condition
+
+	cond      *condition // showif or hideif
+	dependsOn []string   // json names of parent fields; dependson='department'
+
+	tp        string // golang type name: string, int, []string, []uint8
+	isSlice   bool
+	inputType string       // toInputType() - or repeat for slices of structs
+	elem      reflect.Type // struct type of the rows of a repeatable group
+}
+
+// tag returns the value of key from the 'form' struct tag
+func (fs *fieldSchema) tag(key string) string {
+	val, _ := fs.parsed.get(key)
+	return val
+}
+
+// formSchema holds the reflected metadata of a struct type;
+// shared by Form(), Card() and CSVLine()
+type formSchema struct {
+	name      string // struct type name
+	fields    []fieldSchema
+	upload    bool            // has file inputs - requires multipart form
+	autofocus string          // json name of the last field with autofocus
+	parents   map[string]bool // json names of fields with dependent selects
+}
+
+var schemaCache sync.Map // reflect.Type => *formSchema
+
+// schemaOf returns the cached schema of a struct type;
+// reflecting it on first use
+func schemaOf(typeOfS reflect.Type) *formSchema {
+	if sch, ok := schemaCache.Load(typeOfS); ok {
+		return sch.(*formSchema)
+	}
+	sch, _ := schemaCache.LoadOrStore(typeOfS, compileSchema(typeOfS))
+	return sch.(*formSchema)
+}
+
+func compileSchema(typeOfS reflect.Type) *formSchema {
+
+	sch := &formSchema{
+		name:    typeOfS.Name(),
+		fields:  make([]fieldSchema, 0, typeOfS.NumField()),
+		parents: map[string]bool{},
+	}
+
+	for i := 0; i < typeOfS.NumField(); i++ {
+
+		sf := typeOfS.Field(i)
+
+		fs := fieldSchema{
+			index:    i,
+			name:     sf.Name,
+			exported: sf.Name[0:1] == strings.ToUpper(sf.Name[0:1]),
+		}
+
+		fs.inpName = sf.Tag.Get("json") // i.e. date_layout
+		fs.inpName = strings.Replace(fs.inpName, ",omitempty", "", -1)
+
+		fs.attrs = sf.Tag.Get("form")
+		fs.skip = fs.attrs == "-"
+		if !fs.skip {
+			fs.parsed, fs.tagErr = parseFormTag(fs.attrs)
+		}
+		if fs.tagErr == nil {
+			fs.cond, fs.tagErr = conditionOf(fs.parsed)
+		}
+
+		fs.label = labelize(fs.inpName)
+		if fs.tag("label") != "" {
+			fs.label = fs.tag("label")
+		}
+
+		fs.tp = sf.Type.Name() // primitive type name: string, int
+		if sf.Type.Kind() == reflect.Slice {
+			fs.isSlice = true
+			fs.tp = "[]" + sf.Type.Elem().Name() // []byte => []uint8
+		}
+		fs.inputType = toInputType(fs.tp, fs.attrs)
+		if isUploadType(sf.Type) {
+			fs.inputType = "file"
+		} else if fs.isSlice && sf.Type.Elem().Kind() == reflect.Struct {
+			fs.inputType = "repeat" // compiled lazily by rowSchema() - rows may refer to the outer struct
+			fs.elem = sf.Type.Elem()
+		}
+
+		if fs.inputType == "file" {
+			sch.upload = true
+		}
+		for _, parent := range strings.Split(fs.tag("dependson"), ",") {
+			if parent = strings.TrimSpace(parent); parent != "" {
+				fs.dependsOn = append(fs.dependsOn, parent)
+				sch.parents[parent] = true
+			}
+		}
+		if fs.tag("autofocus") != "" {
+			sch.autofocus = fs.inpName
+		}
+
+		sch.fields = append(sch.fields, fs)
+	}
+
+	return sch
+}
+
+// tagErr returns the first syntax error in the 'form' tags
+// of the rendered fields - as *FieldError
+func (sch *formSchema) tagErr() error {
+	for _, fs := range sch.fields {
+		if fs.exported && !fs.skip && fs.tagErr != nil {
+			return &FieldError{Field: fs.name, Err: fs.tagErr}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package struc2frm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchemaCache(t *testing.T) {
+
+	tp := reflect.TypeOf(entryForm{})
+	sch1 := schemaOf(tp)
+	sch2 := schemaOf(tp)
+	if sch1 != sch2 {
+		t.Errorf("schema should be compiled once per type")
+	}
+
+	fs := sch1.fields[0]
+	if fs.inpName != "department" || fs.label != "Department/Abteilung" || fs.inputType != "select" || fs.tag("accesskey") != "p" {
+		t.Errorf("unexpected schema for first field: %+v", fs)
+	}
+	if sch1.autofocus != "items2" {
+		t.Errorf("want autofocus on items2 - got %v", sch1.autofocus)
+	}
+}
+
+var benchRows = func() []entryForm {
+	rows := make([]entryForm, 5000)
+	for i := range rows {
+		rows[i] = entryForm{
+			Department: "ub",
+			HashKey:    "abc",
+			Groups:     i,
+			Items:      "Brutsyum, Zusoh",
+			Date:       time.Now().Format("2006-01-02"),
+			CheckThis:  i%2 == 0,
+		}
+	}
+	return rows
+}()
+
+// BenchmarkCSVLine exports 5000 rows - schema compiled once
+func BenchmarkCSVLine(b *testing.B) {
+	s2f := New()
+	for n := 0; n < b.N; n++ {
+		for _, row := range benchRows {
+			s2f.CSVLine(row, ";")
+		}
+	}
+}
+
+// BenchmarkCSVLineUncached exports 5000 rows - schema compiled for every row;
+// go test -bench CSVLine compares both
+func BenchmarkCSVLineUncached(b *testing.B) {
+	s2f := New()
+	tp := reflect.TypeOf(entryForm{})
+	for n := 0; n < b.N; n++ {
+		for _, row := range benchRows {
+			schemaCache.Delete(tp)
+			s2f.CSVLine(row, ";")
+		}
+	}
+}
+
+func BenchmarkForm(b *testing.B) {
+	s2f := New()
+	for n := 0; n < b.N; n++ {
+		s2f.Form(benchRows[0])
+	}
+}
+
+func BenchmarkFormUncached(b *testing.B) {
+	s2f := New()
+	tp := reflect.TypeOf(entryForm{})
+	for n := 0; n < b.N; n++ {
+		schemaCache.Delete(tp)
+		s2f.Form(benchRows[0])
+	}
+}
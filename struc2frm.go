@@ -5,61 +5,102 @@ package struc2frm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
-	"io/ioutil"
 	"log"
+	"log/slog"
+	"maps"
 	"net"
 	"net/http"
-	"path"
 	"reflect"
 	"regexp"
-	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/go-playground/form"
-	"github.com/pkg/errors"
 )
 
-var defaultHTML = ""
+// Option is a select/dropdown or radiogroup option;
+// Val is the label
+type Option struct {
+	Key string `json:"key"`
+	Val string `json:"label"`
 
-func init() {
-	_, filename, _, _ := runtime.Caller(0)
-	sourceDirPath := path.Join(path.Dir(filename), "tpl-main.html")
-	bts, err := ioutil.ReadFile(sourceDirPath)
-	if err != nil {
-		log.Printf("Could not load main template: %v", err)
-		defaultHTML = staticTplMainHTML
-		log.Printf("Loaded %v chars from static.go instead", len(staticTplMainHTML))
-		return
-	}
-	defaultHTML = string(bts)
+	Group    string            `json:"group,omitempty"`    // <optgroup label='...'>; consecutive options of the same group are grouped
+	Disabled bool              `json:"disabled,omitempty"` // shown, but not selectable; i.e. sold out
+	Title    string            `json:"title,omitempty"`    // tooltip
+	Data     map[string]string `json:"data,omitempty"`     // data- attributes; keys without prefix 'data-'
 }
 
-var defaultCSS = ""
+// attrs renders the per-option attributes - with leading space
+func (o Option) attrs() string {
+	w := &bytes.Buffer{}
+	if o.Disabled {
+		fmt.Fprint(w, " disabled")
+	}
+	if o.Title != "" {
+		fmt.Fprintf(w, " title='%v'", template.HTMLEscapeString(o.Title))
+	}
+	keys := make([]string, 0, len(o.Data))
+	for key := range o.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !dataKey.MatchString(key) {
+			continue // logged by validData()
+		}
+		fmt.Fprintf(w, " data-%v='%v'", key, template.HTMLEscapeString(o.Data[key]))
+	}
+	return w.String()
+}
 
-func init() {
-	_, filename, _, _ := runtime.Caller(0)
-	sourceDirPath := path.Join(path.Dir(filename), "default.css")
-	bts, err := ioutil.ReadFile(sourceDirPath)
-	if err != nil {
-		log.Printf("Could not load default CSS: %v", err)
-		defaultCSS = staticDefaultCSS // <style> embedding added while rendering
-		log.Printf("Loaded %v chars from static.go instead", len(staticDefaultCSS))
-		return
+// dataKey restricts the keys of Option.Data;
+// HTML escaping leaves spaces and '=' in attribute names - i.e. 'x onmouseover'
+var dataKey = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// validData drops and logs Data keys not matching dataKey;
+// opts is copied before changes - it may be shared by SetOptions()
+func (s2f *s2FT) validData(field string, opts options) options {
+	copied := false
+	for i, o := range opts {
+		data := map[string]string{}
+		dropped := false
+		for key, val := range o.Data {
+			if !dataKey.MatchString(key) {
+				s2f.logger().Warn("option data key dropped", "field", field, "option", o.Key, "key", key)
+				dropped = true
+				continue
+			}
+			data[key] = val
+		}
+		if !dropped {
+			continue
+		}
+		if !copied {
+			opts = slices.Clone(opts)
+			copied = true
+		}
+		opts[i].Data = data
 	}
-	defaultCSS = string(bts) // <style> embedding added while rendering
+	return opts
 }
 
-type option struct {
-	Key, Val string
+// Label returns Val - prefixed by its group, if any
+func (o Option) Label() string {
+	if o.Group == "" {
+		return o.Val
+	}
+	return fmt.Sprintf("%v - %v", o.Group, o.Val)
 }
 
-type options []option
+type options []Option
 
 // CardViewOptions governs the display of the rendering of Card()
 type CardViewOptions struct {
@@ -79,6 +120,10 @@ type s2FT struct {
 	Salt        string // generated from MAC address - see below
 	FormTimeout int    // hours until a form post is rejected - CSRF token
 
+	Honeypot    bool          // render a CSS-concealed input - bots fill it in, humans do not
+	MinFillTime time.Duration // posts arriving faster after rendering are rejected; zero means no check
+	MaxFillTime time.Duration // posts arriving later after rendering are rejected; against replay; zero means no limit
+
 	FocusFirstError bool // setfocus(); takes precedence over focus attribute
 	ForceSubmit     bool // show submit, despite having only auto-changing selects
 
@@ -88,8 +133,24 @@ type s2FT struct {
 
 	CSS string // general formatting - provided defaults can be replaced
 
-	selectOptions map[string]options // select inputs get their options from here
-	errors        map[string]string  // validation errors by json name of input
+	Logger *slog.Logger // structured logging with attributes form and instance; no-op by default
+
+	CSPNonce  string // Content-Security-Policy nonce for <style> and <script>; also replaces inline event handlers by data- attributes
+	AssetsURL string // if set, i.e. '/struc2frm-assets/', CSS and JS are referenced from AssetsHandler() instead of inlined
+
+	OptionsURL string // if set, i.e. '/struc2frm-options', dependent selects are refreshed from OptionsHandler() instead of submitting the form
+
+	MaxMemory     int64 // bytes of a multipart form held in memory; larger uploads go into temp files; default 24 MiB
+	MaxUploadSize int64 // bytes of a multipart request body; larger requests are rejected; zero means no limit
+
+	selectOptions    map[string]options         // select inputs get their options from here
+	optionsSources   map[string]OptionsSource   // resolved at rendering - taking precedence over selectOptions
+	optionsProviders map[string]OptionsProvider // dependent selects - taking precedence over optionsSources
+	errors           map[string]string          // validation errors by json name of input
+
+	messages map[string]message // non-error messages by json name of input - AddMessage()
+
+	wizard *wizardNav // set by Wizard while rendering a step
 
 	CardViewOptions
 }
@@ -120,9 +181,12 @@ func New() *s2FT {
 
 		Salt:        addressMAC,
 		FormTimeout: 2,
+		MaxFillTime: 24 * time.Hour,
 
-		selectOptions: map[string]options{},
-		errors:        map[string]string{},
+		selectOptions:    map[string]options{},
+		optionsSources:   map[string]OptionsSource{},
+		optionsProviders: map[string]OptionsProvider{},
+		errors:           map[string]string{},
 
 		FocusFirstError: true,
 		ForceSubmit:     false,
@@ -131,7 +195,11 @@ func New() *s2FT {
 		IndentAddenum:  2 * (4 + 4), // horizontal padding and margin
 		VerticalSpacer: 0.6,
 
-		CSS: defaultCSS,
+		CSS: defaultCSS(),
+
+		Logger: slog.New(discardHandler{}),
+
+		MaxMemory: 24 << 20,
 	}
 	s2f.InstanceID = fmt.Sprint(time.Now().UnixNano())
 	s2f.InstanceID = s2f.InstanceID[len(s2f.InstanceID)-8:] // use the last 8 digits
@@ -146,50 +214,44 @@ func New() *s2FT {
 func (s2f *s2FT) CloneForRequest() *s2FT {
 	clone := *s2f
 	clone.errors = map[string]string{}
+	clone.messages = nil
+	// SetOptions() on the clone must not race with other requests
+	clone.selectOptions = maps.Clone(s2f.selectOptions)
+	clone.optionsSources = maps.Clone(s2f.optionsSources)
+	clone.optionsProviders = maps.Clone(s2f.optionsProviders)
 	clone.InstanceID = fmt.Sprint(time.Now().UnixNano())
 	clone.InstanceID = clone.InstanceID[len(clone.InstanceID)-8:] // last 8 digits
 	return &clone
 }
 
-var defaultS2F = New()
+// defaultS2F serves the package funcs;
+// set in init() - New() reads the embedded assets, which log by pkgLogger()
+var defaultS2F *s2FT
 
-/*
-	.+      any chars
-	'       boundary one
-	(.+?)   any chars, ? means not greedy
-	'       boundary two
-	.+      any chars
-
-	this leads nowhere, since opening and closing boundary are the same
-*/
-var comma = regexp.MustCompile(`.*'(.*,.*?)'.*`)
-
-func commaInsideQuotesBAD(s string) bool {
-	matches := comma.FindAllStringSubmatch(s, -1) // -1 returns all matches
-	log.Printf("%v matches for \n\t%v\n\t%+v\n\n", len(matches), s, matches)
-	return comma.MatchString(s)
+func init() {
+	defaultS2F = New()
 }
 
-func commaInsideQuotes(s string) bool {
-	parts := strings.Split(s, "'")
-	for idx, pt := range parts {
-		if idx%2 == 0 {
-			continue
-		}
-		// log.Printf("checking part %v - %v", idx, pt)
-		if strings.Contains(pt, ",") {
-			return true
-		}
+// nonceAttr returns the nonce attribute for <style> and <script> tags;
+// empty outside of CSP mode
+func (s2f *s2FT) nonceAttr() string {
+	if s2f.CSPNonce == "" {
+		return ""
 	}
-	return false
+	return fmt.Sprintf(" nonce='%v'", template.HTMLEscapeString(s2f.CSPNonce))
 }
 
 func (s2f *s2FT) RenderCSS(w io.Writer) {
 
 	// generic CSS
-	fmt.Fprint(w, "\n<style>\n")
-	fmt.Fprint(w, s2f.CSS)
-	fmt.Fprint(w, "\n</style>\n")
+	if s2f.AssetsURL != "" && s2f.CSS == defaultCSS() {
+		// default CSS from AssetsHandler() - a custom s2f.CSS is inlined
+		fmt.Fprintf(w, "\n<link rel='stylesheet' href='%v' />\n", s2f.assetURL("struc2frm.css"))
+	} else {
+		fmt.Fprintf(w, "\n<style%v>\n", s2f.nonceAttr())
+		fmt.Fprint(w, s2f.CSS)
+		fmt.Fprint(w, "\n</style>\n")
+	}
 
 	if s2f.Indent == 0 { // using additional generic specs - for instance with media query
 		return
@@ -197,7 +259,7 @@ func (s2f *s2FT) RenderCSS(w io.Writer) {
 
 	// instance specific
 	specific := `
-<style>
+<style__nonce__>
 	/* instance specifics */
 	div.struc2frm-  label {
 		min-width: %vpx;
@@ -219,33 +281,77 @@ func (s2f *s2FT) RenderCSS(w io.Writer) {
 		s2f.Indent+s2f.IndentAddenum,
 	)
 	specific = strings.ReplaceAll(specific, "div.struc2frm-", fmt.Sprintf("div.struc2frm-%v", s2f.InstanceID))
+	specific = strings.ReplaceAll(specific, "__nonce__", s2f.nonceAttr())
 
 	fmt.Fprint(w, specific)
 }
 
 func (s2f *s2FT) verticalSpacer() string {
+	if s2f.CSPNonce != "" {
+		return "\t<div class='s2f-vspace'>&nbsp;</div>" // height from renderCSPStyles()
+	}
 	return fmt.Sprintf("\t<div style='height:%3.1frem'>&nbsp;</div>", s2f.VerticalSpacer)
 }
 
+// labelStyleClass is the class replacing the inline 'form' tag label-style in CSP mode;
+// repeat group rows append the index of their sub field
+func labelStyleClass(idxs ...int) string {
+	cls := "s2f-ls"
+	for _, idx := range idxs {
+		cls += fmt.Sprintf("-%v", idx)
+	}
+	return cls
+}
+
+// renderCSPStyles writes the inline styles of labels and spacers
+// as nonce'd class rules - in CSP mode inline style attributes are blocked
+func (s2f *s2FT) renderCSPStyles(w io.Writer, sch *formSchema) {
+	if s2f.CSPNonce == "" {
+		return
+	}
+	div := fmt.Sprintf("div.struc2frm-%v", s2f.InstanceID)
+	fmt.Fprintf(w, "\n<style%v>\n", s2f.nonceAttr())
+	fmt.Fprintf(w, "\t%v  div.s2f-vspace { height: %3.1frem; }\n", div, s2f.VerticalSpacer)
+	fmt.Fprintf(w, "\t%v  label.s2f-valign-top { vertical-align: top; }\n", div)
+	for i := range sch.fields {
+		fs := &sch.fields[i]
+		if style := fs.tag("label-style"); style != "" {
+			fmt.Fprintf(w, "\t%v  .%v { %v }\n", div, labelStyleClass(fs.index), style)
+		}
+		if fs.inputType != "repeat" {
+			continue
+		}
+		for _, sub := range rowFields(fs) {
+			if style := sub.tag("label-style"); style != "" {
+				fmt.Fprintf(w, "\t%v  .%v { %v }\n", div, labelStyleClass(fs.index, sub.index), style)
+			}
+		}
+	}
+	fmt.Fprint(w, "</style>\n")
+}
+
 // SetOptions to prepare dropdown/select options - with keys and labels
-// for rendering in Form()
-func (s2f *s2FT) SetOptions(nameJSON string, keys, labels []string) {
+// for rendering in Form();
+// returns ErrOptionsMismatch - leaving the options empty - if lengths differ.
+// See SetOptionsSource() for options resolved at rendering.
+func (s2f *s2FT) SetOptions(nameJSON string, keys, labels []string) error {
 	if s2f.selectOptions == nil {
 		s2f.selectOptions = map[string]options{}
 	}
 	s2f.selectOptions[nameJSON] = options{} // always reset options to prevent accumulation of options on clones
 
 	if len(keys) != len(labels) {
-		s2f.selectOptions[nameJSON] = append(s2f.selectOptions[nameJSON], option{"key", "keys and labels length does not match"})
-	} else {
-		for i, key := range keys {
-			s2f.selectOptions[nameJSON] = append(s2f.selectOptions[nameJSON], option{key, labels[i]})
-		}
+		return fmt.Errorf("%w - %v: %v keys, %v labels", ErrOptionsMismatch, nameJSON, len(keys), len(labels))
 	}
+	for i, key := range keys {
+		s2f.selectOptions[nameJSON] = append(s2f.selectOptions[nameJSON], Option{Key: key, Val: labels[i]})
+	}
+	return nil
 }
 
 // AddError adds a validation message;
 // key 'global' writes msg on top of form.
+// See AddMessage() for info, success and warning messages.
 func (s2f *s2FT) AddError(nameJSON string, msg string) {
 	if s2f.errors == nil {
 		s2f.errors = map[string]string{}
@@ -270,8 +376,16 @@ func (s2f *s2FT) AddErrors(errs map[string]string) {
 	}
 }
 
-// DefaultOptionKey gives the value to be selected on form init
+// DefaultOptionKey gives the value to be selected on form init;
+// options sources are resolved without request context
 func (s2f *s2FT) DefaultOptionKey(name string) string {
+	if src, ok := s2f.optionsSources[name]; ok {
+		opts, err := src.Options(context.Background())
+		if err != nil || len(opts) == 0 {
+			return ""
+		}
+		return opts[0].Key
+	}
 	if s2f.selectOptions == nil {
 		return ""
 	}
@@ -281,11 +395,23 @@ func (s2f *s2FT) DefaultOptionKey(name string) string {
 	return s2f.selectOptions[name][0].Key
 }
 
-// rendering <option val='...'>...</option> tags
+// rendering <option val='...'>...</option> tags;
+// keys and labels are escaped;
+// options with Group are wrapped into <optgroup>
 func (opts options) HTML(selecteds []string) string {
 	w := &bytes.Buffer{}
 	// log.Printf("select options - selecteds %v", selecteds)
+	group := ""
 	for _, o := range opts {
+		if o.Group != group {
+			if group != "" {
+				fmt.Fprint(w, "\t\t</optgroup>\n")
+			}
+			if o.Group != "" {
+				fmt.Fprintf(w, "\t\t<optgroup label='%v'>\n", template.HTMLEscapeString(o.Group))
+			}
+			group = o.Group
+		}
 		found := false
 		for _, selected := range selecteds {
 			if o.Key == selected {
@@ -293,44 +419,21 @@ func (opts options) HTML(selecteds []string) string {
 				// log.Printf("found %v", o.Key)
 			}
 		}
+		key, val := template.HTMLEscapeString(o.Key), template.HTMLEscapeString(o.Val)
 		if found {
-			fmt.Fprintf(w, "\t\t<option value='%v' selected >%v</option>\n", o.Key, o.Val)
+			fmt.Fprintf(w, "\t\t<option value='%v'%v selected >%v</option>\n", key, o.attrs(), val)
 		} else {
-			fmt.Fprintf(w, "\t\t<option value='%v'          >%v</option>\n", o.Key, o.Val)
+			fmt.Fprintf(w, "\t\t<option value='%v'%v          >%v</option>\n", key, o.attrs(), val)
 		}
 	}
-	return w.String()
-}
-
-// rendering <input type='radio' /> tags
-func (opts options) Radio(name string, selecteds []string) string {
-	w := &bytes.Buffer{}
-	for _, o := range opts {
-		checked := ""
-		for _, selected := range selecteds {
-			if o.Key == selected {
-				checked = "checked=\"checked\""
-				// log.Printf("selected %v", o.Key)
-			}
-		}
-
-		// A click on a label for a *radio* does not focus the input; possibly because input name is not unique
-		// Possible remedy stackoverflow.com/questions/13273806/
-		// rejected for its HTML ugliness
-		if o.Val != "" {
-			fmt.Fprintf(w, "\t\t<label for='%v' >%v</label>\n", name, o.Val)
-		}
-
-		fmt.Fprintf(w,
-			"\t\t<input type='radio' name='%v' value='%v' %v />\n",
-			name, o.Key, checked,
-		)
-
+	if group != "" {
+		fmt.Fprint(w, "\t\t</optgroup>\n")
 	}
 	return w.String()
 }
 
-/*ValToString converts reflect.Value to string.
+/*
+ValToString converts reflect.Value to string.
 
 go-playground/form.Decode nicely converts all kins of request.Form strings
 into the desired struct types.
@@ -378,12 +481,20 @@ func toInputType(t, attrs string) string {
 			return "select"
 		case "radiogroup":
 			return "radiogroup"
+		case "checkboxgroup":
+			if t == "[]string" {
+				return "checkboxgroup"
+			}
 		}
 		return "text"
 	case "int", "float64", "[]int", "[]float64":
 		switch structTag(attrs, "subtype") { // might want dropdown, for instance for list of years
 		case "select":
 			return "select"
+		case "checkboxgroup":
+			if strings.HasPrefix(t, "[]") {
+				return "checkboxgroup"
+			}
 		}
 		return "number"
 	case "bool", "[]bool":
@@ -400,70 +511,51 @@ func toInputType(t, attrs string) string {
 
 // parsing the struct tag 'form';
 // returning a *single* value for argument key;
+// keys are matched exactly; malformed tags yield "" - see parseFormTag();
 // i.e. "maxlength='42',size='28',suffix='optional'"
-//       key=size
-//       returns 28
+//
+//	key=size
+//	returns 28
 func structTag(tags, key string) string {
-	tagss := strings.Split(tags, ",")
-	for _, a := range tagss {
-		aLow := strings.ToLower(a)
-		if strings.HasPrefix(aLow, key) {
-			kv := strings.Split(a, "=")
-			if len(kv) == 2 {
-				return strings.Trim(kv[1], "'")
-			}
-		}
-	}
-	return ""
+	ft, _ := parseFormTag(tags)
+	val, _ := ft.get(key)
+	return val
 }
 
 // convert the struct tag 'form' to html input attributes;
 // mostly replacing comma with single space;
 // i.e. "maxlength='42',size='28',suffix='optional'"
-func structTagsToAttrs(tags string) string {
-	tagss := strings.Split(tags, ",")
+func (s2f *s2FT) structTagsToAttrs(ft formTag) string {
 	ret := ""
-	for _, t := range tagss {
-		t = strings.TrimSpace(t)
-		tl := strings.ToLower(t) // tag lower
-		switch {
-		case strings.HasPrefix(tl, "subtype="): // string - [date,textarea,select] - not an HTML attribute; kept for debugging
-			ret += " " + t
-		case strings.HasPrefix(tl, "size="): // visible width of input field
-			ret += " " + t
-		case strings.HasPrefix(tl, "maxlength="): // digits of input data
-			ret += " " + t
-		case strings.HasPrefix(tl, "max="): // for input number
-			ret += " " + t
-		case strings.HasPrefix(tl, "min="): // for input number
-			ret += " " + t
-		case strings.HasPrefix(tl, "step="): // for input number - special value 'any'
-			ret += " " + t
-		case strings.HasPrefix(tl, "pattern="): // client side validation; i.e. date layout [0-9\\.\\-/]{10}
-			ret += " " + t
-		case strings.HasPrefix(tl, "placeholder="): // a watermark showing expected input; i.e. 2006/01/02 15:04
-			ret += " " + t
-		case strings.HasPrefix(tl, "rows="): // for texarea
-			ret += " " + t
-		case strings.HasPrefix(tl, "cols="): // for texarea
-			ret += " " + t
-		case strings.HasPrefix(tl, "accept="): // file upload extension
-			ret += " " + t
-		case strings.HasPrefix(tl, "onchange"): // file upload extension
-			ret += " " + "onchange='javascript:this.form.submit();'"
-		case strings.HasPrefix(tl, "wildcardselect"): // show extra input next to select - to select options
-			ret += " " + t
-		case strings.HasPrefix(tl, "accesskey="): // goes into input, not into label
-			ret += " " + t
-		case strings.HasPrefix(tl, "title="): // mouse over tooltip - alt
-			ret += " " + t
-		case strings.HasPrefix(tl, "autocapitalize="): // 'off' prevents upper case for first word on mobile phones
-			ret += " " + t
-		case strings.HasPrefix(tl, "inputmode="): // 'numeric' shows only numbers keysboard on mobile phones
-			ret += " " + t
-		case strings.HasPrefix(tl, "multiple"): // dropdown/select - select multiple items; no value
+	for _, t := range ft {
+		switch strings.ToLower(t.Key) {
+		case "subtype", // string - [date,textarea,select] - not an HTML attribute; kept for debugging
+			"size",           // visible width of input field
+			"maxlength",      // digits of input data
+			"max",            // for input number
+			"min",            // for input number
+			"step",           // for input number - special value 'any'
+			"pattern",        // client side validation; i.e. date layout [0-9\\.\\-/]{10}
+			"placeholder",    // a watermark showing expected input; i.e. 2006/01/02 15:04
+			"rows",           // for texarea
+			"cols",           // for texarea
+			"accept",         // file upload extension
+			"accesskey",      // goes into input, not into label
+			"title",          // mouse over tooltip - alt
+			"autocapitalize", // 'off' prevents upper case for first word on mobile phones
+			"inputmode":      // 'numeric' shows only numbers keysboard on mobile phones
+			if t.hasVal {
+				ret += " " + t.String()
+			}
+		case "onchange": // submit on change
+			ret += s2f.onchangeAttr()
+		case "wildcardselect": // show extra input next to select - to select options
+			ret += " " + t.String()
+		case "multiple": // dropdown/select - select multiple items; no value
 			ret += " " + "multiple" // only the attribute; no value
-		case strings.HasPrefix(tl, "autofocus"):
+		case "required": // client side validation; no value
+			ret += " " + "required" // only the attribute; no value
+		case "autofocus":
 			ret += " " + "autofocus" // only the attribute; no value
 		default:
 			// "label="       is not converted into an attribute
@@ -476,9 +568,17 @@ func structTagsToAttrs(tags string) string {
 	return ret
 }
 
+// onchangeAttr submits the form on change - for 'form' tag onchange;
+// with leading space
+func (s2f *s2FT) onchangeAttr() string {
+	if s2f.useDataAttrs() {
+		return " " + "data-s2f-submit='true'" // wired up by struc2frm.js
+	}
+	return " " + "onchange='javascript:this.form.submit();'"
+}
+
 // for example 'Date layout' with accesskey 't' becomes 'Da<u>t</u>e layout'
-func accessKeyify(s, attrs string) string {
-	ak := structTag(attrs, "accesskey")
+func accessKeyify(s, ak string) string {
 	if ak == "" {
 		return s
 	}
@@ -538,15 +638,28 @@ func labelize(s string) string {
 // ParseMultipartForm parses an HTTP request form
 // with file attachments
 func ParseMultipartForm(r *http.Request) error {
+	return New().ParseMultipartForm(r)
+}
+
+// ParseMultipartForm is like package func ParseMultipartForm();
+// but logs to s2f.Logger and uses s2f.MaxMemory and s2f.MaxUploadSize;
+// oversized requests yield *http.MaxBytesError.
+func (s2f *s2FT) ParseMultipartForm(r *http.Request) error {
 
 	if r.Method == "GET" {
 		return nil
 	}
 
-	const _24K = (1 << 20) * 24
-	err := r.ParseMultipartForm(_24K)
+	maxMemory := s2f.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = 24 << 20
+	}
+	if s2f.MaxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, s2f.MaxUploadSize)
+	}
+	err := r.ParseMultipartForm(maxMemory)
 	if err != nil {
-		log.Printf("Parse multipart form error: %v\n", err)
+		s2f.logger().Error("parse multipart form", "err", err)
 		return err
 	}
 	return nil
@@ -555,6 +668,12 @@ func ParseMultipartForm(r *http.Request) error {
 // ExtractUploadedFile extracts a file from an HTTP POST request.
 // It needs the request form to be prepared with ParseMultipartForm.
 func ExtractUploadedFile(r *http.Request, names ...string) (bts []byte, fname string, err error) {
+	return New().ExtractUploadedFile(r, names...)
+}
+
+// ExtractUploadedFile is like package func ExtractUploadedFile();
+// but logs to s2f.Logger
+func (s2f *s2FT) ExtractUploadedFile(r *http.Request, names ...string) (bts []byte, fname string, err error) {
 
 	if r.Method == "GET" {
 		return
@@ -564,60 +683,88 @@ func ExtractUploadedFile(r *http.Request, names ...string) (bts []byte, fname st
 	if len(names) > 0 {
 		name = names[0]
 	}
+	lg := s2f.logger().With("field", name)
 
 	_, fheader, err := r.FormFile(name)
 	if err != nil {
-		log.Printf("Error unpacking upload bytes from post request: %v\n", err)
+		lg.Warn("unpacking upload bytes from post request", "err", err)
 		return
 	}
 
 	fname = fheader.Filename
-	log.Printf("Uploaded filename = %+v", fname)
+	lg = lg.With("filename", fname)
 
 	rdr, err := fheader.Open()
 	if err != nil {
-		log.Printf("Error opening uploaded file: %v\n", err)
+		lg.Error("opening uploaded file", "err", err)
 		return
 	}
 	defer rdr.Close()
 
-	bts, err = ioutil.ReadAll(rdr)
+	bts, err = io.ReadAll(rdr)
 	if err != nil {
-		log.Printf("Error reading uploaded file: %v\n", err)
+		lg.Error("reading uploaded file", "err", err)
 		return
 	}
 
-	log.Printf("Extracted %v bytes from uploaded file", len(bts))
+	lg.Debug("extracted uploaded file", "bytes", len(bts))
 	return
 
 }
 
 // Form takes a struct instance
-// and turns it into an HTML form.
+// and turns it into an HTML form;
+// errors are rendered as text - use RenderForm() to handle them.
 func (s2f *s2FT) Form(intf interface{}) template.HTML {
+	w := &bytes.Buffer{}
+	if err := s2f.RenderForm(w, intf); err != nil {
+		return template.HTML(template.HTMLEscapeString(fmt.Sprintf("struct2form.Form() - %v", err)))
+	}
+	return template.HTML(w.String())
+}
+
+// RenderForm writes the HTML form for struct instance intf to wr.
+// Returns ErrNotStruct, *FieldError for invalid 'form' tags
+// or the first error of wr.
+// Arguments are checked before anything is written.
+func (s2f *s2FT) RenderForm(wr io.Writer, intf interface{}) error {
+	return s2f.RenderFormContext(context.Background(), wr, intf)
+}
+
+// RenderFormContext is RenderForm() - with ctx passed to options providers;
+// i.e. the request context.
+func (s2f *s2FT) RenderFormContext(ctx context.Context, wr io.Writer, intf interface{}) error {
 
 	v := reflect.ValueOf(intf) // interface val
-	typeOfS := v.Type()
 	// v = v.Elem() // de reference
 
-	if v.Kind().String() != "struct" {
-		return template.HTML(fmt.Sprintf("struct2form.Form() - arg1 must be struct - is %v", v.Kind()))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w - is %v", ErrNotStruct, v.Kind())
 	}
 
-	w := &bytes.Buffer{}
+	sch := schemaOf(v.Type())
+	if err := sch.tagErr(); err != nil {
+		return err
+	}
+	resolved, err := s2f.resolveOptions(ctx, v, sch)
+	if err != nil {
+		return err
+	}
+
+	w := &errWriter{w: wr}
 
 	needSubmit := false // only select with onchange:submit() ?
+	needWiring := false // data- attributes instead of inline event handlers in CSP or assets mode
+	needScript := false // showif/hideif and dependson - always handled by struc2frm.js
 
 	// collect fields with initial focus and fields with errors
 	inputWithFocus := ""      // first input having an autofocus attribute
 	firstInputWithError := "" // first input having an error message
 	if s2f.FocusFirstError {
-		for i := 0; i < v.NumField(); i++ {
-			inpName := typeOfS.Field(i).Tag.Get("json") // i.e. date_layout
-			inpName = strings.Replace(inpName, ",omitempty", "", -1)
-			_, hasError := s2f.errors[inpName]
+		for _, fs := range sch.fields {
+			_, hasError := s2f.errors[fs.inpName]
 			if hasError {
-				firstInputWithError = inpName
+				firstInputWithError = fs.inpName
 				break
 			}
 		}
@@ -626,37 +773,21 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 	if firstInputWithError != "" {
 		inputWithFocus = firstInputWithError
 	} else {
-		for i := 0; i < v.NumField(); i++ {
-			inpName := typeOfS.Field(i).Tag.Get("json") // i.e. date_layout
-			inpName = strings.Replace(inpName, ",omitempty", "", -1)
-			attrs := typeOfS.Field(i).Tag.Get("form") // i.e. form:"maxlength='42',size='28'"
-			if structTag(attrs, "autofocus") != "" {
-				inputWithFocus = inpName
-			}
-		}
+		inputWithFocus = sch.autofocus
 	}
 
 	s2f.RenderCSS(w)
+	s2f.renderCSPStyles(w, sch)
 
 	// one class selector for general - one for specific instance
 	fmt.Fprintf(w, "<div class='struc2frm struc2frm-%v'>\n", s2f.InstanceID)
 
 	if s2f.ShowHeadline {
-		fmt.Fprintf(w, "<h3>%v</h3>\n", labelize(typeOfS.Name()))
+		fmt.Fprintf(w, "<h3>%v</h3>\n", labelize(sch.name))
 	}
 
 	// file upload requires distinct form attribute
-	uploadPostForm := false
-	for i := 0; i < v.NumField(); i++ {
-		tp := v.Field(i).Type().Name() // primitive type name: string, int
-		if typeOfS.Field(i).Type.Kind() == reflect.Slice {
-			tp = "[]" + typeOfS.Field(i).Type.Elem().Name()
-		}
-		if toInputType(tp, "") == "file" {
-			uploadPostForm = true
-			break
-		}
-	}
+	uploadPostForm := sch.upload
 
 	if s2f.FormTag {
 		if uploadPostForm {
@@ -668,46 +799,47 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 	}
 
 	if errMsg, ok := s2f.errors["global"]; ok {
-		fmt.Fprintf(w, "\t<p class='error-block' >%v</p>\n", errMsg)
+		fmt.Fprintf(w, "\t<p class='error-block' role='alert' >%v</p>\n", errMsg)
 	}
+	s2f.renderGlobalMessage(w)
 
 	fmt.Fprintf(w, "\t<input name='token'    type='hidden'   value='%v' />\n", s2f.FormToken())
+	s2f.renderBotGuard(w)
+	if s2f.wizard != nil {
+		s2f.wizard.renderState(w)
+	}
+	s2f.renderErrorSummary(w, v, sch)
 
 	fieldsetOpen := false
 
 	// Render fields
-	for i := 0; i < v.NumField(); i++ {
+	for fIdx := range sch.fields {
 
-		// struct field name; i.e. Name, Birthdate
-		fn := typeOfS.Field(i).Name
-		if fn[0:1] != strings.ToUpper(fn[0:1]) { // only used to find unexported fields; otherwise json tag name is used
+		fs := &sch.fields[fIdx]
+		if !fs.exported { // only used to find unexported fields; otherwise json tag name is used
 			continue // skip unexported
 		}
 
-		inpName := typeOfS.Field(i).Tag.Get("json") // i.e. date_layout
-		inpName = strings.Replace(inpName, ",omitempty", "", -1)
-		inpLabel := labelize(inpName)
+		inpName := fs.inpName // i.e. date_layout
+		inpLabel := fs.label
 
-		attrs := typeOfS.Field(i).Tag.Get("form") // i.e. form:"maxlength='42',size='28'"
-
-		if structTag(attrs, "label") != "" {
-			inpLabel = structTag(attrs, "label")
-		}
-
-		if strings.Contains(attrs, ", ") || strings.Contains(attrs, ", ") {
-			return template.HTML(fmt.Sprintf("struct2form.Form() - field %v: tag 'form' cannot contain ', ' or ' ,' ", inpName))
+		if fs.skip {
+			continue
 		}
 
-		if commaInsideQuotes(attrs) {
-			return template.HTML(fmt.Sprintf("struct2form.Form() - field %v: tag 'form' - use &comma; instead of ',' inside of single quotes values", inpName))
+		if fs.tag("role") == "status" { // server message - not an input
+			if valStr := ValToString(v.Field(fs.index)); valStr != "" {
+				fmt.Fprintf(w, "\t<p class='global-info' role='status' id='%v' >%v</p>\n", inpName, template.HTMLEscapeString(valStr))
+			}
+			continue
 		}
 
-		if attrs == "-" {
-			continue
+		if fs.tag("onchange") != "" || fs.tag("wildcardselect") != "" {
+			needWiring = true
 		}
 
 		// getting the value and the type of the iterated struct field
-		val := v.Field(i)
+		val := v.Field(fs.index)
 		if false {
 			// if our entry form struct would contain pointer fields...
 			val = reflect.Indirect(val) // pointer converted to value
@@ -715,10 +847,7 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 			val = val.Elem()            // what is the difference?
 		}
 
-		tp := v.Field(i).Type().Name() // primitive type name: string, int
-		if typeOfS.Field(i).Type.Kind() == reflect.Slice {
-			tp = "[]" + v.Type().Field(i).Type.Elem().Name() // []byte => []uint8
-		}
+		tp := fs.tp // primitive type name: string, int, []string, []uint8
 
 		valStr := ValToString(val)
 		valStrs := []string{valStr} // for select multiple='false'
@@ -726,7 +855,7 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 		// for select multiple='true'
 		// 		if tp == []string or []int or []float64 ...
 		// 		unpack slice from checkbox arrays or select/dropdown multiple
-		if typeOfS.Field(i).Type.Kind() == reflect.Slice {
+		if fs.isSlice {
 
 			// valSlice := reflect.MakeSlice(val.Type(), val.Cap(), val.Len())
 			// valSlice := val.Slice(0, val.Len())
@@ -757,87 +886,140 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 			}
 		}
 
+		// showif/hideif - a disabled fieldset excludes its inputs from submission
+		condOpen := fs.cond != nil && fs.inputType != "fieldset"
+		if condOpen {
+			needScript = true
+			hidden := ""
+			if sch.hidden(v, fs) {
+				hidden = " hidden disabled"
+			}
+			fmt.Fprintf(w, "\t<fieldset class='s2f-cond' %v%v>\n", fs.cond.attr(), hidden)
+		}
+
 		errMsg, hasError := s2f.errors[inpName]
 		if hasError {
-			fmt.Fprintf(w, "\t<p class='error-block' >%v</p>\n", errMsg)
+			fmt.Fprintf(w, "\t<p class='error-block' id='%v_err' >%v</p>\n", inpName, errMsg)
 		}
+		s2f.renderMessage(w, inpName, inpName)
+
+		attrs := s2f.structTagsToAttrs(fs.parsed) + s2f.ariaAttrs(fs)
 
-		labelStyle := structTag(attrs, "label-style") // for instance irregular width - overriding CSS style
+		labelStyle := fs.tag("label-style") // for instance irregular width - overriding CSS style
 
 		// label positioning for tall inputs
 		specialVAlign := ""
-		if toInputType(tp, attrs) == "textarea" {
+		if fs.inputType == "textarea" {
 			specialVAlign = "vertical-align: top;"
 		}
-		if toInputType(tp, attrs) == "select" {
-			if structTag(attrs, "multiple") != "" {
+		if fs.inputType == "select" {
+			if fs.tag("multiple") != "" {
 				specialVAlign = "vertical-align: top;"
 			}
 		}
-		if toInputType(tp, attrs) != "separator" &&
-			toInputType(tp, attrs) != "fieldset" {
+		legendAttr := fmt.Sprintf("class='group-legend' style='%v'", labelStyle)
+		labelAttr := fmt.Sprintf("style='%v%v'", labelStyle, specialVAlign)
+		if s2f.CSPNonce != "" { // classes - rules by renderCSPStyles()
+			classes := []string{}
+			if labelStyle != "" {
+				classes = append(classes, labelStyleClass(fs.index))
+			}
+			legendAttr = fmt.Sprintf("class='%v'", strings.Join(append([]string{"group-legend"}, classes...), " "))
+			if specialVAlign != "" {
+				classes = append(classes, "s2f-valign-top")
+			}
+			labelAttr = fmt.Sprintf("class='%v'", strings.Join(classes, " "))
+		}
+		legend := fmt.Sprintf( // instead of label for groups of radios or checkboxes
+			"\t<legend %v >%v</legend>\n",
+			legendAttr, accessKeyify(inpLabel, fs.tag("accesskey")),
+		)
+		if fs.inputType != "separator" &&
+			fs.inputType != "fieldset" &&
+			fs.inputType != "radiogroup" &&
+			fs.inputType != "checkboxgroup" &&
+			fs.inputType != "repeat" {
 			fmt.Fprintf(w,
-				"\t<label for='%s' style='%v%v' >%v</label>\n", // no whitespace - input immediately afterwards
-				inpName, labelStyle, specialVAlign, accessKeyify(inpLabel, attrs),
+				"\t<label for='%s' %v >%v</label>\n", // no whitespace - input immediately afterwards
+				inpName, labelAttr, accessKeyify(inpLabel, fs.tag("accesskey")),
 			)
 		}
 
 		// various inputs
-		switch toInputType(tp, attrs) {
+		switch fs.inputType {
 		case "checkbox":
 			needSubmit = true
 			checked := ""
 			if val.Bool() {
 				checked = "checked"
 			}
-			fmt.Fprintf(w, "\t<input type='%v' name='%v' id='%v' value='%v' %v %v />\n", toInputType(tp, attrs), inpName, inpName, "true", checked, structTagsToAttrs(attrs))
+			fmt.Fprintf(w, "\t<input type='%v' name='%v' id='%v' value='%v' %v %v />\n", fs.inputType, inpName, inpName, "true", checked, attrs)
 			fmt.Fprintf(w, "\t<input type='hidden' name='%v' value='false' />", inpName)
 		case "file":
 			needSubmit = true
 			//              <input type="file" name="upload" id="upload" value="ignored.json" accept=".json" >
 			fmt.Fprintf(w, "\t<input type='%v'   name='%v'     id='%v'     value='%v' %v />",
-				toInputType(tp, attrs), inpName, inpName, "ignored.json", structTagsToAttrs(attrs),
+				fs.inputType, inpName, inpName, "ignored.json", attrs,
 			)
+			if name := sch.uploadName(v, fs); name != "" { // browsers cannot prefill file inputs - nor resend the file
+				fmt.Fprintf(w, "<span class='s2f-upload-name' id='%v_file' >previously: %v - please select again</span>", inpName, template.HTMLEscapeString(name))
+			}
 		case "date", "time":
 			needSubmit = true
 			//              <input type="date" name="myDate" max="1989-10-29"  min="2001-01-02">
 			fmt.Fprintf(w, "\t<input type='%v'   name='%v'     id='%v'     value='%v' %v />",
-				toInputType(tp, attrs), inpName, inpName, val, structTagsToAttrs(attrs),
+				fs.inputType, inpName, inpName, val, attrs,
 			)
 		case "textarea":
 			needSubmit = true
 			fmt.Fprintf(w, "\t<textarea name='%v' id='%v' %v />",
-				inpName, inpName, structTagsToAttrs(attrs),
+				inpName, inpName, attrs,
 			)
 			fmt.Fprint(w, val)
 			fmt.Fprintf(w, "</textarea>")
 		case "radiogroup":
-			if structTag(attrs, "onchange") == "" {
+			if fs.tag("onchange") == "" {
 				needSubmit = true // select without auto submit => needs submit button
 			}
-			fmt.Fprint(w, "\t<div class='select-arrow'>\n")
-			fmt.Fprint(w, "\t<div class='radio-group'>\n")
-			fmt.Fprint(w, s2f.selectOptions[inpName].Radio(inpName, valStrs))
-			fmt.Fprint(w, "\t</div>")
-			fmt.Fprint(w, "\t</div>")
+			// radios of the same name are one tab stop - arrow keys move between them
+			fmt.Fprintf(w, "\t<fieldset class='radio-group' role='radiogroup' id='%v'%v >\n", inpName, s2f.ariaAttrs(fs))
+			fmt.Fprint(w, legend)
+			onchange := ""
+			if fs.tag("onchange") != "" {
+				onchange = s2f.onchangeAttr() // parity with select
+			}
+			fmt.Fprint(w, resolved[inpName].inputs("radio", inpName, valStrs, onchange))
+			fmt.Fprint(w, "\t</fieldset>")
 
-		case "select":
-			if structTag(attrs, "onchange") == "" {
+		case "select", "checkboxgroup":
+			if fs.tag("onchange") == "" {
 				needSubmit = true // select without auto submit => needs submit button
 			}
-			fmt.Fprint(w, "\t<div class='select-arrow'>\n")
-			fmt.Fprintf(w, "\t<select name='%v' id='%v' %v />\n", inpName, inpName, structTagsToAttrs(attrs))
-			fmt.Fprint(w, s2f.selectOptions[inpName].HTML(valStrs))
-			fmt.Fprint(w, "\t</select>\n")
-			fmt.Fprint(w, "\t</div>")
-			if structTag(attrs, "wildcardselect") != "" {
+			if fs.inputType == "checkboxgroup" {
+				fmt.Fprintf(w, "\t<fieldset class='checkbox-group' id='%v'%v >\n", inpName, s2f.ariaAttrs(fs))
+				fmt.Fprint(w, legend)
+				fmt.Fprint(w, resolved[inpName].Checkboxes(inpName, valStrs))
+				fmt.Fprint(w, groupMarker(inpName))
+				fmt.Fprint(w, "\t</fieldset>")
+			} else {
+				fmt.Fprint(w, "\t<div class='select-arrow'>\n")
+				fmt.Fprintf(w, "\t<select name='%v' id='%v' %v%v />\n", inpName, inpName, attrs, s2f.dependsOnAttrs(fs))
+				fmt.Fprint(w, resolved[inpName].HTML(valStrs))
+				fmt.Fprint(w, "\t</select>\n")
+				fmt.Fprint(w, "\t</div>")
+			}
+			if fs.tag("wildcardselect") != "" {
 				fmt.Fprint(w, "\t\t<div class='wildcardselect'>\n")
 				// onchange only triggers on blur
 				// onkeydown makes too much noise
 				// oninput is just perfect
+				oninput := "oninput='javascript:selectOptions(this);'"
+				if s2f.useDataAttrs() {
+					oninput = "data-s2f-wildcard='true'" // wired up by struc2frm.js
+				}
 				fmt.Fprintf(w, `		  <input type='text' name='%v' id='%v' value='%v'
 					title='case sensitive | multiple patterns with * | separated by ; | ! negates'
-					oninput='javascript:selectOptions(this);'
+					%v
 					maxlength='40'
 					xxtabindex=-1
 					placeholder='a*;b*'
@@ -845,103 +1027,28 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 					inpName+"_so",
 					inpName+"_so",
 					"",
+					oninput,
 				)
 				fmt.Fprint(w, "\n\t\t</div>")
-				/*
-					JS function is printed repeatedly for multiple selects
-					and multiple forms per request.
-					The complexity of keeping track would be even more ugly.
-				*/
-				fmt.Fprintf(w, `
-				<script type="text/javascript">
-
-				var wildcardselectDebug = false;
-
-				function matchRule(str, rule) {
-					// define an arrow function with =>
-					// creating the func escapeRegex()
-					// escape all regex control characters; i.e. [ with \[
-					// this could be moved out into a plain JS function
-					var escapeRegex = (strArg) => strArg.replace(/([.*+?^=!:${}()|\[\]\/\\])/g, "\\$1");
-
-					// split by *
-					// escape regex chars of the parts
-					// join  by .*
-					// "."  matches single character, except newline or line terminator
-					// ".*" matches any string containing zero or more characters
-					rule = rule.split("*").map(escapeRegex).join(".*");
-
-					// "^" is expression start
-					// "$" is expression end
-					rule = "^" + rule + "$"
-
-					if (wildcardselectDebug) {
-						console.log("     testing rule '" + rule + "' on str '" + str + "'");
-					}
-
-					// create a regular expression object for matching string
-					var regex = new RegExp(rule);
-
-					//Returns true if it finds a match, otherwise it returns false
-					return regex.test(str);
-				}
-
-				function selectOptions(src) {
-					// console.log(src)
-					if (src) {
-						var myName = src.getAttribute("name");
-						// console.log("on input " + myName);
-						var selectName = myName.substring(0, myName.length - 3);
-						// console.log("  corresponding select is " + selectName);
-
-						var select = document.getElementById(selectName);
-						if (select) {
-							var wildcards = src.value;
-							var wildcardsArray = wildcards.split(";");
-							for (idx = 0; idx < wildcardsArray.length; ++idx) {
-								var wildcard = wildcardsArray[idx];
-								var negate = false;
-								if (wildcard.charAt(0) === "!") {
-									wildcard = wildcard.substring(1);
-									var negate = true;
-								}
-								for (var i = 0, l = select.options.length, o; i < l; i++) {
-									o = select.options[i];
-									var doesMatch = matchRule(o.text, wildcard);
-									// if (negate) {
-									// 	doesMatch = !doesMatch;
-									// }
-									if (doesMatch && !negate) {
-										o.selected = true;
-										if (wildcardselectDebug) {
-											console.log("   selected     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
-										}
-									} else if (doesMatch && negate) {
-										o.selected = false;
-										if (wildcardselectDebug) {
-											console.log(" unselected     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
-										}
-									} else {
-										if (wildcardselectDebug) {
-											console.log("   no match     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
-										}
-									}
-								}
-
-							}
-
-						}
-					}
+				if !s2f.useDataAttrs() {
+					/*
+						JS function is printed repeatedly for multiple selects
+						and multiple forms per request.
+						The complexity of keeping track would be even more ugly.
+						Set AssetsURL to reference it once instead.
+					*/
+					s2f.renderScript(w)
 				}
-
-				</script>
-
-				`)
 			}
 
+		case "repeat":
+			needSubmit = true
+			needScript = true // adds and removes rows
+			s2f.renderRepeat(w, fs, val, legend, resolved)
+
 		case "separator":
 			// when separator has an explicit label value
-			if structTag(attrs, "label") != "" {
+			if fs.tag("label") != "" {
 				fmt.Fprintf(w, "\t<div class='struc2frm-static'>%v</div>", inpLabel)
 			} else {
 				fmt.Fprint(w, "\t<div  class='separator'></div>")
@@ -957,18 +1064,30 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 		default:
 			// plain vanilla input
 			needSubmit = true
-			fmt.Fprintf(w, "\t<input type='%v' name='%v' id='%v' value='%v' %v />", toInputType(tp, attrs), inpName, inpName, val, structTagsToAttrs(attrs))
+			fmt.Fprintf(w, "\t<input type='%v' name='%v' id='%v' value='%v' %v />", fs.inputType, inpName, inpName, val, attrs)
 
 		}
 
-		sfx := structTag(attrs, "suffix")
+		if sch.parents[inpName] || len(fs.dependsOn) > 0 {
+			needScript = true // refreshes dependent selects
+		}
+		if sch.parents[inpName] {
+			// without JavaScript, dependent selects are refreshed by submitting
+			fmt.Fprintf(w, "<noscript><button type='submit' name='btnRefresh' value='%v' >Refresh</button></noscript>", inpName)
+		}
+
+		sfx := fs.tag("suffix")
 		if sfx != "" {
-			fmt.Fprintf(w, "<span class='postlabel' >%s</span>", sfx)
+			fmt.Fprintf(w, "<span class='postlabel' id='%v_sfx' >%s</span>", inpName, sfx)
+		}
+		if title := fs.tag("title"); title != "" && fs.inputType != "separator" && fs.inputType != "fieldset" {
+			// the title attribute is not reliably read by screen readers
+			fmt.Fprintf(w, "<span class='s2f-sr-only' id='%v_title' >%v</span>", inpName, template.HTMLEscapeString(title))
 		}
 
-		if toInputType(tp, attrs) != "separator" &&
-			toInputType(tp, attrs) != "fieldset" &&
-			structTag(attrs, "nobreak") == "" {
+		if fs.inputType != "separator" &&
+			fs.inputType != "fieldset" &&
+			fs.tag("nobreak") == "" {
 			fmt.Fprintf(w, "\n")
 			fmt.Fprintf(w, s2f.verticalSpacer())
 		}
@@ -976,13 +1095,19 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 		// close input with newline
 		fmt.Fprintf(w, "\n")
 
+		if condOpen {
+			fmt.Fprint(w, "\t</fieldset>\n")
+		}
+
 	}
 
 	if fieldsetOpen {
 		fmt.Fprint(w, "</fieldset>\n")
 	}
 
-	if needSubmit || s2f.ForceSubmit {
+	if s2f.wizard != nil {
+		s2f.wizard.renderButtons(w, s2f.verticalSpacer())
+	} else if needSubmit || s2f.ForceSubmit {
 		// name should *not* be 'submit'
 		// avoiding error on this.form.submit()
 		// 'submit is not a function' stackoverflow.com/questions/833032/
@@ -996,12 +1121,16 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 	}
 	fmt.Fprint(w, "</div><!-- </div class='struc2frm'... -->\n")
 
+	if needScript || s2f.useDataAttrs() && needWiring {
+		s2f.renderScript(w)
+	}
+
 	if inputWithFocus != "" {
 		// finding form by name - setting focus by name;
 		// this repeats or overrides the autofocus mechanism;
 		// is it always last in timeline?
 		fmt.Fprintf(w, `
-			<script type="text/javascript">
+			<script type="text/javascript"%v>
 
 			// getting the form object
 			var frm;
@@ -1039,13 +1168,10 @@ func (s2f *s2FT) Form(intf interface{}) template.HTML {
 
 
 			</script>
-			`, s2f.Name, inputWithFocus)
+			`, s2f.nonceAttr(), s2f.Name, inputWithFocus)
 	}
 
-	// global replacements
-	ret := strings.ReplaceAll(w.String(), "&comma;", ",")
-
-	return template.HTML(ret)
+	return w.err
 }
 
 // HTML takes a struct instance
@@ -1074,25 +1200,41 @@ func indentedDump(v interface{}) string {
 // deriving the 'populated' return value from the existence of the CSRF token.
 // We *could* call Validate() on ptr2Struct if implemented;
 // but valid is *more* than just populated.
+//
+// Errors are ErrMissingToken, ErrTokenExpired, ErrTokenInvalid, ErrHoneypotFilled
+// or DecodeErrors for values not convertible into their struct fields.
 func Decode(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
-	err = r.ParseForm()
-	if err != nil {
-		return false, errors.Wrapf(err, "cannot parse form: %v<br>\n <pre>%v</pre>", err, indentedDump(r.Form))
-	}
-	return decode(r, ptr2Struct)
+	return New().Decode(r, ptr2Struct)
 }
 
 // DecodeMultipartForm decodes the form into an instance of struct
 // and checks the token against CSRF attacks (https://en.wikipedia.org/wiki/Cross-site_request_forgery)
 func DecodeMultipartForm(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
-	err = ParseMultipartForm(r)
+	return New().DecodeMultipartForm(r, ptr2Struct)
+}
+
+// Decode is like package func Decode();
+// but uses Salt, FormTimeout and MinFillTime of s2f;
+// posts arriving faster than MinFillTime yield ErrSubmittedTooFast.
+func (s2f *s2FT) Decode(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
+	err = r.ParseForm()
 	if err != nil {
-		return false, errors.Wrapf(err, "cannot parse multi part form: %v<br>\n <pre>%v</pre>", err, indentedDump(r.Form))
+		return false, fmt.Errorf("struc2frm: cannot parse form: %w", err)
 	}
-	return decode(r, ptr2Struct)
+	return s2f.decode(r, ptr2Struct)
 }
 
-func decode(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
+// DecodeMultipartForm is like package func DecodeMultipartForm();
+// but uses the settings of s2f - see s2f.Decode()
+func (s2f *s2FT) DecodeMultipartForm(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
+	err = s2f.ParseMultipartForm(r)
+	if err != nil {
+		return false, fmt.Errorf("struc2frm: cannot parse multi part form: %w", err)
+	}
+	return s2f.decode(r, ptr2Struct)
+}
+
+func (s2f *s2FT) decode(r *http.Request, ptr2Struct interface{}) (populated bool, err error) {
 
 	//
 	// check for empty requests
@@ -1100,22 +1242,42 @@ func decode(r *http.Request, ptr2Struct interface{}) (populated bool, err error)
 	ln := len(r.Form)              // request form is empty
 	// sm := r.FormValue("btnSubmit") != ""  // submit btn would not be present in single dropdown forms with onclick
 	if ln > 0 && !hasToken {
-		log.Printf("warning: request params ignored, due to missing validation token")
+		s2f.logger().Warn("request params ignored, due to missing validation token", "params", ln)
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return false, nil // page views with query params; i.e. ?utm_source=...
+		}
+		return false, ErrMissingToken
 	}
-	if ln < 1 || !hasToken {
+	if ln < 1 {
 		return false, nil
 	}
 
-	err = New().ValidateFormToken(r.Form.Get("token"))
+	err = s2f.ValidateFormToken(r.Form.Get("token"))
 	if err != nil {
-		return true, errors.Wrap(err, "form token exists; but invalid")
+		return true, err
 	}
 
+	err = s2f.checkBotGuard(r)
+	if err != nil {
+		return true, err
+	}
+
+	vals := resetGroups(r.Form, ptr2Struct)
+	resetRows(ptr2Struct)
 	dec := form.NewDecoder()
 	dec.SetTagName("json")
-	err = dec.Decode(ptr2Struct, r.Form)
+	err = dec.Decode(ptr2Struct, vals)
+	fileErrs := s2f.decodeFiles(r, ptr2Struct)
 	if err != nil {
-		return true, errors.Wrapf(err, "cannot decode form: %v<br>\n <pre>%v</pre>", err, indentedDump(r.Form))
+		err = newDecodeErrors(err, vals, ptr2Struct)
+		if des, ok := err.(DecodeErrors); ok {
+			maps.Copy(des, fileErrs)
+		}
+		return true, err
+	}
+	dropBlankRows(ptr2Struct)
+	if len(fileErrs) > 0 {
+		return true, fileErrs
 	}
 
 	// this belongs outside of the library into application side
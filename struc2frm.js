@@ -0,0 +1,333 @@
+// struc2frm.js - served by AssetsHandler() or inlined by Form()
+
+var wildcardselectDebug = false;
+
+function matchRule(str, rule) {
+	// define an arrow function with =>
+	// creating the func escapeRegex()
+	// escape all regex control characters; i.e. [ with \[
+	// this could be moved out into a plain JS function
+	var escapeRegex = (strArg) => strArg.replace(/([.*+?^=!:${}()|\[\]\/\\])/g, "\\$1");
+
+	// split by *
+	// escape regex chars of the parts
+	// join  by .*
+	// "."  matches single character, except newline or line terminator
+	// ".*" matches any string containing zero or more characters
+	rule = rule.split("*").map(escapeRegex).join(".*");
+
+	// "^" is expression start
+	// "$" is expression end
+	rule = "^" + rule + "$"
+
+	if (wildcardselectDebug) {
+		console.log("     testing rule '" + rule + "' on str '" + str + "'");
+	}
+
+	// create a regular expression object for matching string
+	var regex = new RegExp(rule);
+
+	//Returns true if it finds a match, otherwise it returns false
+	return regex.test(str);
+}
+
+// s2fSelectables returns the options of a select
+// or adapters with text and selected for the checkboxes of a checkbox group
+function s2fSelectables(elem) {
+	if (elem.tagName === "SELECT") {
+		return elem.options;
+	}
+	var items = [];
+	var cbs = elem.querySelectorAll("input[type=checkbox]");
+	for (var i = 0; i < cbs.length; i++) {
+		(function (cb) {
+			items.push({
+				text: cb.labels && cb.labels.length > 0 ? cb.labels[0].textContent : cb.value,
+				set selected(val) { cb.checked = val; },
+			});
+		})(cbs[i]);
+	}
+	return items;
+}
+
+function selectOptions(src) {
+	// console.log(src)
+	if (src) {
+		var myName = src.getAttribute("name");
+		// console.log("on input " + myName);
+		var selectName = myName.substring(0, myName.length - 3);
+		// console.log("  corresponding select is " + selectName);
+
+		var select = document.getElementById(selectName);
+		if (select) {
+			var wildcards = src.value;
+			var wildcardsArray = wildcards.split(";");
+			for (idx = 0; idx < wildcardsArray.length; ++idx) {
+				var wildcard = wildcardsArray[idx];
+				var negate = false;
+				if (wildcard.charAt(0) === "!") {
+					wildcard = wildcard.substring(1);
+					var negate = true;
+				}
+				var items = s2fSelectables(select);
+				for (var i = 0, l = items.length, o; i < l; i++) {
+					o = items[i];
+					var doesMatch = matchRule(o.text, wildcard);
+					// if (negate) {
+					// 	doesMatch = !doesMatch;
+					// }
+					if (doesMatch && !negate) {
+						o.selected = true;
+						if (wildcardselectDebug) {
+							console.log("   selected     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
+						}
+					} else if (doesMatch && negate) {
+						o.selected = false;
+						if (wildcardselectDebug) {
+							console.log(" unselected     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
+						}
+					} else {
+						if (wildcardselectDebug) {
+							console.log("   no match     " + o.text + " - wildcard '" + wildcard + "' - negation " + negate);
+						}
+					}
+				}
+
+			}
+
+		}
+	}
+}
+
+// s2fFieldValues returns the current values of the inputs named name;
+// checked boxes and radios, selected options, or plain values;
+// the hidden 'false' companion of a checkbox counts only if the box is unchecked
+function s2fFieldValues(frm, name) {
+	var vals = [];
+	var fallback = [];
+	var els = frm.elements;
+	for (var i = 0; i < els.length; i++) {
+		var el = els[i];
+		if (el.name !== name || el.disabled) {
+			continue;
+		}
+		if (el.type === "checkbox" || el.type === "radio") {
+			if (el.checked) {
+				vals.push(el.value);
+			}
+		} else if (el.type === "hidden") {
+			fallback.push(el.value);
+		} else if (el.tagName === "SELECT") {
+			for (var j = 0; j < el.options.length; j++) {
+				if (el.options[j].selected) {
+					vals.push(el.options[j].value);
+				}
+			}
+		} else {
+			vals.push(el.value);
+		}
+	}
+	return vals.length > 0 ? vals : fallback;
+}
+
+// s2fConditions toggles fieldsets with data-s2f-showif / data-s2f-hideif='field=val1|val2';
+// hidden fieldsets are disabled - their inputs are not submitted
+function s2fConditions(frm) {
+	var conds = frm.querySelectorAll("[data-s2f-showif],[data-s2f-hideif]");
+	for (var i = 0; i < conds.length; i++) {
+		var hide = conds[i].hasAttribute("data-s2f-hideif");
+		var cond = conds[i].getAttribute(hide ? "data-s2f-hideif" : "data-s2f-showif");
+		var pos = cond.indexOf("=");
+		var wants = cond.substring(pos + 1).split("|");
+		var vals = s2fFieldValues(frm, cond.substring(0, pos));
+		var match = vals.some(function (val) { return wants.indexOf(val) > -1; });
+		var visible = match !== hide;
+		conds[i].hidden = !visible;
+		conds[i].disabled = !visible;
+	}
+}
+
+// s2fRefreshDependents reloads the options of selects with data-s2f-dependson
+// listing the changed field src;
+// from data-s2f-options - or by submitting the form, if there is no options URL
+function s2fRefreshDependents(src) {
+	var frm = src.form;
+	var deps = frm.querySelectorAll("select[data-s2f-dependson]");
+	for (var i = 0; i < deps.length; i++) {
+		var dep = deps[i];
+		var parents = dep.getAttribute("data-s2f-dependson").split(",");
+		if (parents.indexOf(src.name) < 0) {
+			continue;
+		}
+		var url = dep.getAttribute("data-s2f-options");
+		if (!url || !window.fetch) {
+			frm.submit();
+			return;
+		}
+		var params = new URLSearchParams();
+		params.set("field", dep.name);
+		parents.forEach(function (parent) {
+			s2fFieldValues(frm, parent).forEach(function (val) {
+				params.append(parent, val);
+			});
+		});
+		s2fLoadOptions(dep, url + (url.indexOf("?") > -1 ? "&" : "?") + params.toString());
+	}
+}
+
+// s2fLoadOptions replaces the options of select dep - keeping the selection where possible;
+// the change event cascades to further dependent selects
+function s2fLoadOptions(dep, url) {
+	fetch(url, { credentials: "same-origin" })
+		.then(function (resp) {
+			if (!resp.ok) {
+				throw new Error("options request failed: " + resp.status);
+			}
+			return resp.json();
+		})
+		.then(function (opts) {
+			var selecteds = s2fFieldValues(dep.form, dep.name);
+			while (dep.firstChild) {
+				dep.removeChild(dep.firstChild); // options and optgroups
+			}
+			var grp = null;
+			opts.forEach(function (opt) {
+				var el = document.createElement("option");
+				el.value = opt.key;
+				el.text = opt.label;
+				el.disabled = !!opt.disabled;
+				el.selected = selecteds.indexOf(opt.key) > -1 && !el.disabled;
+				if (opt.title) {
+					el.title = opt.title;
+				}
+				for (var key in opt.data || {}) {
+					el.setAttribute("data-" + key, opt.data[key]);
+				}
+				if (!opt.group) {
+					grp = null;
+					dep.appendChild(el);
+					return;
+				}
+				if (!grp || grp.label !== opt.group) {
+					grp = document.createElement("optgroup");
+					grp.label = opt.group;
+					dep.appendChild(grp);
+				}
+				grp.appendChild(el);
+			});
+			dep.dispatchEvent(new Event("change", { bubbles: true }));
+		})
+		.catch(function (err) {
+			console.log(err);
+			dep.form.submit(); // full page fallback
+		});
+}
+
+// s2fRepeatRows returns the rows of the repeatable group grp - without the template row
+function s2fRepeatRows(grp) {
+	return grp.querySelectorAll(":scope > [data-s2f-row]");
+}
+
+// s2fRepeatButtons shows the add and remove buttons of repeatable group grp -
+// disabled at data-s2f-max and data-s2f-min rows
+function s2fRepeatButtons(grp) {
+	var cnt = s2fRepeatRows(grp).length;
+	var minRows = parseInt(grp.getAttribute("data-s2f-min"), 10) || 0;
+	var maxRows = parseInt(grp.getAttribute("data-s2f-max"), 10) || 0;
+	grp.querySelectorAll("[data-s2f-add]").forEach(function (btn) {
+		btn.hidden = false;
+		btn.disabled = maxRows > 0 && cnt >= maxRows;
+	});
+	grp.querySelectorAll("[data-s2f-remove]").forEach(function (btn) {
+		btn.hidden = false;
+		btn.disabled = cnt <= minRows;
+	});
+}
+
+// s2fRenumberRows rewrites the row index in names, ids and references to ids;
+// items[2].qty becomes items[1].qty after the removal of a row -
+// the decoder expects no gaps
+function s2fRenumberRows(grp) {
+	var name = grp.getAttribute("data-s2f-repeat");
+	var group = name.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+	var nameRx = new RegExp("^" + group + "\\[(\\d+|__i__)\\]");
+	var idRx = new RegExp("(^|\\s)" + group + "_(\\d+|__i__)_", "g");
+	var rows = s2fRepeatRows(grp);
+	for (var i = 0; i < rows.length; i++) {
+		rows[i].querySelectorAll("[name],[id],[for],[aria-describedby]").forEach(function (el) {
+			if (el.hasAttribute("name")) {
+				el.setAttribute("name", el.getAttribute("name").replace(nameRx, name + "[" + i + "]"));
+			}
+			["id", "for", "aria-describedby"].forEach(function (attr) {
+				if (el.hasAttribute(attr)) {
+					el.setAttribute(attr, el.getAttribute(attr).replace(idRx, "$1" + name + "_" + i + "_"));
+				}
+			});
+		});
+	}
+	s2fRepeatButtons(grp);
+}
+
+// s2fRepeatInit removes the blank rows rendered for browsers without JavaScript -
+// and shows the add and remove buttons instead
+function s2fRepeatInit() {
+	document.querySelectorAll("[data-s2f-repeat]").forEach(function (grp) {
+		grp.querySelectorAll(":scope > [data-s2f-blank]").forEach(function (row) {
+			row.remove();
+		});
+		s2fRenumberRows(grp);
+	});
+}
+
+if (document.readyState === "loading") {
+	document.addEventListener("DOMContentLoaded", s2fRepeatInit);
+} else {
+	s2fRepeatInit();
+}
+
+// wiring of data- attributes,
+// replacing inline event handlers in CSP mode and in assets mode;
+// event delegation works for forms rendered before and after loading this script;
+// the guard prevents double handling if the script is included repeatedly
+if (!window.struc2frmWired) {
+	window.struc2frmWired = true;
+	document.addEventListener("change", function (ev) {
+		if (ev.target.matches && ev.target.matches("[data-s2f-submit]")) {
+			ev.target.form.submit();
+		}
+	});
+	document.addEventListener("input", function (ev) {
+		if (ev.target.matches && ev.target.matches("[data-s2f-wildcard]")) {
+			selectOptions(ev.target);
+		}
+	});
+	// dependson
+	document.addEventListener("change", function (ev) {
+		if (ev.target.form && ev.target.name) {
+			s2fRefreshDependents(ev.target);
+		}
+	});
+	// showif/hideif - initial state is rendered by the server
+	["change", "input"].forEach(function (evType) {
+		document.addEventListener(evType, function (ev) {
+			if (ev.target.form) {
+				s2fConditions(ev.target.form);
+			}
+		});
+	});
+	// repeatable groups
+	document.addEventListener("click", function (ev) {
+		var btn = ev.target.closest && ev.target.closest("[data-s2f-add],[data-s2f-remove]");
+		if (!btn) {
+			return;
+		}
+		var grp = btn.closest("[data-s2f-repeat]");
+		if (btn.hasAttribute("data-s2f-add")) {
+			var tpl = grp.querySelector(":scope > template");
+			grp.insertBefore(tpl.content.cloneNode(true), tpl);
+		} else {
+			btn.closest("[data-s2f-row]").remove();
+		}
+		s2fRenumberRows(grp);
+	});
+}
@@ -0,0 +1,191 @@
+package struc2frm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tagPair is one key-value pair of the 'form' struct tag;
+// i.e. maxlength='42'
+type tagPair struct {
+	Key    string // as written; compared case-insensitively
+	Val    string // unquoted, unescaped; &comma; replaced by ','
+	hasVal bool   // multiple='true' vs. multiple
+	quoted bool   // maxlength='42' vs. maxlength=42
+}
+
+// String renders the pair as HTML attribute;
+// quoted and unquoted values are HTML escaped alike -
+// entities written by the author, i.e. &amp;, are kept
+func (tp tagPair) String() string {
+	if !tp.hasVal {
+		return tp.Key
+	}
+	if !tp.quoted {
+		return tp.Key + "=" + escapeAttr(tp.Val)
+	}
+	return fmt.Sprintf("%v='%v'", tp.Key, escapeAttr(tp.Val))
+}
+
+// entity matches a character reference at the start of a string;
+// i.e. &amp; &#39; &#x27;
+var entity = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// escapeAttr escapes s for attribute values like template.HTMLEscapeString(),
+// but leaves existing character references alone
+func escapeAttr(s string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '&':
+			if ref := entity.FindString(s[i:]); ref != "" {
+				sb.WriteString(ref)
+				i += len(ref) - 1
+				continue
+			}
+			sb.WriteString("&amp;")
+		case '<':
+			sb.WriteString("&lt;")
+		case '>':
+			sb.WriteString("&gt;")
+		case '\'':
+			sb.WriteString("&#39;")
+		case '"':
+			sb.WriteString("&#34;")
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// formTag is the parsed 'form' struct tag - in tag order
+type formTag []tagPair
+
+// get returns the value for key - exact, case-insensitive match;
+// the first occurrence wins
+func (ft formTag) get(key string) (string, bool) {
+	for _, tp := range ft {
+		if strings.EqualFold(tp.Key, key) {
+			return tp.Val, true
+		}
+	}
+	return "", false
+}
+
+// TagError describes a syntax error in a 'form' struct tag
+type TagError struct {
+	Tag string // the complete 'form' tag
+	Pos int    // byte offset of the error in Tag
+	Msg string
+}
+
+func (te *TagError) Error() string {
+	return fmt.Sprintf("tag 'form' at position %v: %v - in %v", te.Pos, te.Msg, te.Tag)
+}
+
+// parseFormTag tokenizes the 'form' struct tag
+//
+//	maxlength='42',size=28,multiple,pattern='[0-9]{2,10}',title='it\'s a=b'
+//
+// Pairs are separated by ','; whitespace around pairs is ignored.
+// Values in single quotes may contain ',' and '='; \' is an escaped quote;
+// all other backslashes are kept - i.e. for regex patterns.
+// Unquoted values end at the next ','.
+// &comma; is still accepted in place of ','.
+func parseFormTag(tag string) (formTag, error) {
+
+	ft := formTag{}
+	pos := 0
+	ln := len(tag)
+
+	skipSpace := func() {
+		for pos < ln && (tag[pos] == ' ' || tag[pos] == '\t') {
+			pos++
+		}
+	}
+	tagErr := func(msg string, args ...interface{}) error {
+		return &TagError{Tag: tag, Pos: pos, Msg: fmt.Sprintf(msg, args...)}
+	}
+
+	for {
+		skipSpace()
+		if pos == ln {
+			if len(ft) > 0 {
+				return ft, tagErr("trailing ','")
+			}
+			return ft, nil
+		}
+
+		// key
+		start := pos
+		for pos < ln && tag[pos] != '=' && tag[pos] != ',' && tag[pos] != '\'' {
+			pos++
+		}
+		tp := tagPair{Key: strings.TrimSpace(tag[start:pos])}
+		if tp.Key == "" {
+			return ft, tagErr("missing key")
+		}
+		if strings.ContainsAny(tp.Key, " \t") {
+			return ft, tagErr("key '%v' contains whitespace", tp.Key)
+		}
+		if pos < ln && tag[pos] == '\'' {
+			return ft, tagErr("quote in key '%v' - missing '='", tp.Key)
+		}
+
+		// value
+		if pos < ln && tag[pos] == '=' {
+			pos++
+			tp.hasVal = true
+			skipSpace()
+			if pos < ln && tag[pos] == '\'' {
+				tp.quoted = true
+				quotePos := pos
+				pos++
+				val := &strings.Builder{}
+				closed := false
+				for pos < ln {
+					if tag[pos] == '\\' && pos+1 < ln && tag[pos+1] == '\'' {
+						val.WriteByte('\'')
+						pos += 2
+						continue
+					}
+					if tag[pos] == '\'' {
+						closed = true
+						pos++
+						break
+					}
+					val.WriteByte(tag[pos])
+					pos++
+				}
+				if !closed {
+					pos = quotePos // pointing to the opening quote
+					return ft, tagErr("unterminated quote for key '%v'", tp.Key)
+				}
+				tp.Val = val.String()
+			} else {
+				start = pos
+				for pos < ln && tag[pos] != ',' {
+					if tag[pos] == '\'' {
+						return ft, tagErr("quote inside unquoted value for key '%v'", tp.Key)
+					}
+					pos++
+				}
+				tp.Val = strings.TrimSpace(tag[start:pos])
+			}
+			tp.Val = strings.ReplaceAll(tp.Val, "&comma;", ",") // backwards compatibility
+		}
+		ft = append(ft, tp)
+
+		// separator
+		skipSpace()
+		if pos == ln {
+			return ft, nil
+		}
+		if tag[pos] != ',' {
+			return ft, tagErr("expected ',' after key '%v' - got '%c'", tp.Key, tag[pos])
+		}
+		pos++
+	}
+}
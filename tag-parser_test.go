@@ -0,0 +1,123 @@
+package struc2frm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFormTag(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		key  string
+		want string
+	}{
+		{
+			in:   `maxlength='16',size='16'`,
+			key:  "size",
+			want: "16",
+		},
+		{
+			in:   `maxlength='16',max='100'`,
+			key:  "max",
+			want: "100", // exact match - not maxlength
+		},
+		{
+			in:   `label-style='width:2rem',label='Name'`,
+			key:  "label",
+			want: "Name", // exact match - not label-style
+		},
+		{
+			in:   `pattern='[0-9\.\-/]{2,10}',placeholder='2006/01/02 15:04'`,
+			key:  "pattern",
+			want: `[0-9\.\-/]{2,10}`,
+		},
+		{
+			in:   `pattern='[0-9\.\-/]{2&comma;10}'`,
+			key:  "pattern",
+			want: `[0-9\.\-/]{2,10}`, // backwards compatible
+		},
+		{
+			in:   `title='a=b, c=d',size=3`,
+			key:  "title",
+			want: "a=b, c=d",
+		},
+		{
+			in:   `suffix='it\'s optional'`,
+			key:  "suffix",
+			want: "it's optional",
+		},
+		{
+			in:   `subtype=select, size='1'`,
+			key:  "size",
+			want: "1",
+		},
+		{
+			in:   `multiple,autofocus`,
+			key:  "autofocus",
+			want: "",
+		},
+	}
+	for idx, tt := range tests {
+		ft, err := parseFormTag(tt.in)
+		if err != nil {
+			t.Errorf("idx%2v: %v", idx, err)
+			continue
+		}
+		got, _ := ft.get(tt.key)
+		if got != tt.want {
+			t.Errorf("idx%2v: %-16v is %-16v should be %v", idx, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTagPairString(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`title='Tom &amp; Jerry'`, `title='Tom &amp; Jerry'`}, // no double escaping
+		{`title='Tom & Jerry'`, `title='Tom &amp; Jerry'`},
+		{`title='it\'s <b>'`, `title='it&#39;s &lt;b&gt;'`},
+		{`max=<x>`, `max=&lt;x&gt;`}, // unquoted escaped alike
+		{`max=a&#34;b`, `max=a&#34;b`},
+		{`size=16`, `size=16`},
+		{`multiple`, `multiple`},
+	}
+	for idx, tt := range tests {
+		ft, err := parseFormTag(tt.in)
+		if err != nil || len(ft) != 1 {
+			t.Errorf("idx%2v: %v", idx, err)
+			continue
+		}
+		if got := ft[0].String(); got != tt.want {
+			t.Errorf("idx%2v: %-16v is %-16v should be %v", idx, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormTagErrors(t *testing.T) {
+
+	tests := []struct {
+		in  string
+		pos int
+	}{
+		{in: `label='unterminated`, pos: 6},
+		{in: `size='3'x`, pos: 8},
+		{in: `size=3,`, pos: 7},
+		{in: `=3`, pos: 0},
+		{in: `size=a'b'`, pos: 6},
+	}
+	for idx, tt := range tests {
+		_, err := parseFormTag(tt.in)
+		var te *TagError
+		if !errors.As(err, &te) {
+			t.Errorf("idx%2v: %-16v want TagError - got %v", idx, tt.in, err)
+			continue
+		}
+		if te.Pos != tt.pos {
+			t.Errorf("idx%2v: %-16v want position %v - got %v", idx, tt.in, tt.pos, te.Pos)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package struc2frm
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FileUpload is a struct field type for file inputs;
+// populated by DecodeMultipartForm() - for several files use []FileUpload with 'form' tag multiple.
+// The 'form' tags maxsize and accept are enforced on the server.
+type FileUpload struct {
+	Filename    string // as sent by the browser - without path; untrusted
+	Size        int64  // bytes
+	ContentType string // as sent by the browser; untrusted
+
+	header *multipart.FileHeader
+}
+
+var fileUploadType = reflect.TypeOf(FileUpload{})
+
+// Open returns the content of the uploaded file;
+// read from memory - or from a temp file for uploads beyond s2f.MaxMemory;
+// the caller must close it.
+func (fu FileUpload) Open() (multipart.File, error) {
+	if fu.header == nil {
+		return nil, ErrNoFile
+	}
+	return fu.header.Open()
+}
+
+// String is shown by Card(); i.e. 'report.txt (16 bytes)'
+func (fu FileUpload) String() string {
+	if fu.Filename == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v (%v bytes)", fu.Filename, fu.Size)
+}
+
+func newFileUpload(fh *multipart.FileHeader) FileUpload {
+	return FileUpload{
+		Filename:    fh.Filename,
+		Size:        fh.Size,
+		ContentType: fh.Header.Get("Content-Type"),
+		header:      fh,
+	}
+}
+
+// isUploadType tells whether tp is FileUpload or []FileUpload
+func isUploadType(tp reflect.Type) bool {
+	return tp == fileUploadType || tp.Kind() == reflect.Slice && tp.Elem() == fileUploadType
+}
+
+// parseSize parses the 'form' tag maxsize;
+// i.e. 1024, 512K, 2M or 1G - multiples of 1024
+func parseSize(s string) (int64, error) {
+	num := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	mult := int64(1)
+	if ln := len(num); ln > 0 {
+		switch num[ln-1] {
+		case 'K':
+			mult = 1 << 10
+		case 'M':
+			mult = 1 << 20
+		case 'G':
+			mult = 1 << 30
+		}
+		if mult > 1 {
+			num = num[:ln-1]
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("tag 'form': maxsize='%v' must be bytes - or with suffix K, M, G", s)
+	}
+	return n * mult, nil
+}
+
+// accepts matches fu against the 'form' tag accept;
+// i.e. '.txt,.csv' or 'image/*,application/pdf'
+func accepts(accept string, fu FileUpload) bool {
+	mediaType, _, _ := mime.ParseMediaType(fu.ContentType)
+	for _, tok := range strings.Split(accept, ",") {
+		tok = strings.ToLower(strings.TrimSpace(tok))
+		switch {
+		case tok == "":
+		case strings.HasPrefix(tok, "."):
+			if strings.HasSuffix(strings.ToLower(fu.Filename), tok) {
+				return true
+			}
+		case strings.HasSuffix(tok, "/*"):
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(tok, "*")) {
+				return true
+			}
+		case tok == mediaType:
+			return true
+		}
+	}
+	return false
+}
+
+// checkUpload checks fu against the 'form' tags maxsize and accept of fs
+func (fs *fieldSchema) checkUpload(fu FileUpload) *DecodeError {
+	de := &DecodeError{Field: fs.inpName, Value: fu.Filename, kind: reflect.Struct}
+	if s := fs.tag("maxsize"); s != "" {
+		if limit, err := parseSize(s); err == nil && fu.Size > limit {
+			de.Err, de.hint = ErrFileTooLarge, s
+			return de
+		}
+	}
+	if acc := fs.tag("accept"); acc != "" && !accepts(acc, fu) {
+		de.Err, de.hint = ErrFileType, acc
+		return de
+	}
+	return nil
+}
+
+// decodeFiles populates the file fields of *ptr2Struct
+// from the files of a parsed multipart form;
+// []byte fields get the content of the first file -
+// and the string field named by 'form' tag filename gets its name;
+// files violating maxsize or accept yield DecodeErrors
+func (s2f *s2FT) decodeFiles(r *http.Request, ptr2Struct interface{}) DecodeErrors {
+
+	v := reflect.ValueOf(ptr2Struct)
+	if r.MultipartForm == nil || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+
+	des := DecodeErrors{}
+	sch := schemaOf(v.Type())
+	for fIdx := range sch.fields {
+		fs := &sch.fields[fIdx]
+		if !fs.exported || fs.skip || fs.inputType != "file" {
+			continue
+		}
+		ups := []FileUpload{}
+		for _, fh := range r.MultipartForm.File[fs.inpName] {
+			up := newFileUpload(fh)
+			if de := fs.checkUpload(up); de != nil {
+				s2f.logger().Info("upload rejected", "field", fs.inpName, "filename", up.Filename, "size", up.Size, "err", de.Err)
+				des[fs.inpName] = de
+				break
+			}
+			ups = append(ups, up)
+		}
+		if des[fs.inpName] != nil {
+			ups = nil
+		}
+
+		// overwrite in any case - empty file inputs may have been decoded as zero values
+		fld := v.Field(fs.index)
+		switch {
+		case fld.Type() == fileUploadType:
+			up := FileUpload{}
+			if len(ups) > 0 {
+				up = ups[0]
+			}
+			fld.Set(reflect.ValueOf(up))
+		case isUploadType(fld.Type()):
+			if len(ups) == 0 {
+				fld.Set(reflect.Zero(fld.Type()))
+				continue
+			}
+			fld.Set(reflect.ValueOf(ups))
+		default: // []byte
+			var bts []byte
+			fname := ""
+			if len(ups) > 0 {
+				var err error
+				bts, err = ups[0].readAll()
+				if err != nil {
+					s2f.logger().Error("reading uploaded file", "field", fs.inpName, "filename", ups[0].Filename, "err", err)
+					des[fs.inpName] = &DecodeError{Field: fs.inpName, Value: ups[0].Filename, Err: err, kind: reflect.Struct}
+				} else {
+					fname = ups[0].Filename
+				}
+			}
+			fld.SetBytes(bts)
+			if cfs := sch.filenameField(fs); cfs != nil {
+				v.Field(cfs.index).SetString(fname)
+			}
+		}
+	}
+	return des
+}
+
+// readAll returns the content of the uploaded file
+func (fu FileUpload) readAll() ([]byte, error) {
+	rdr, err := fu.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	return io.ReadAll(rdr)
+}
+
+// filenameField returns the string field named by 'form' tag filename of fs - or nil
+func (sch *formSchema) filenameField(fs *fieldSchema) *fieldSchema {
+	name := fs.tag("filename")
+	if name == "" {
+		return nil
+	}
+	cfs := sch.byInpName(name)
+	if cfs == nil || !cfs.exported || cfs.tp != "string" {
+		return nil
+	}
+	return cfs
+}
+
+// uploadName returns the filenames of file input fs of struct v;
+// browsers do not send files again - the previous upload is shown
+// with a request to select it again
+func (sch *formSchema) uploadName(v reflect.Value, fs *fieldSchema) string {
+	switch val := v.Field(fs.index).Interface().(type) {
+	case FileUpload:
+		return val.Filename
+	case []FileUpload:
+		names := []string{}
+		for _, up := range val {
+			names = append(names, up.Filename)
+		}
+		return strings.Join(names, ", ")
+	}
+	if cfs := sch.filenameField(fs); cfs != nil {
+		return v.Field(cfs.index).String()
+	}
+	return ""
+}
+
+// uploadMessage is DecodeError.Message() for rejected uploads
+func uploadMessage(de *DecodeError) string {
+	switch de.Err {
+	case ErrFileTooLarge:
+		return fmt.Sprintf("file is too large - at most %v", template.HTMLEscapeString(de.hint))
+	case ErrFileType:
+		return fmt.Sprintf("file type is not accepted - expected %v", template.HTMLEscapeString(de.hint))
+	}
+	return ""
+}
@@ -0,0 +1,285 @@
+package struc2frm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+type uploadsFormT struct {
+	Note  string       `json:"note"`
+	Photo FileUpload   `json:"photo"  form:"accept='image/*',maxsize='16'"`
+	Docs  []FileUpload `json:"docs"   form:"multiple='true',accept='.txt,.CSV'"`
+}
+
+type testFile struct {
+	field, name, contentType, content string
+}
+
+// multipartRequest posts files and a form token
+func multipartRequest(t *testing.T, files ...testFile) *http.Request {
+	body := &bytes.Buffer{}
+	mpWriter := multipart.NewWriter(body)
+	for _, f := range files {
+		hdr := textproto.MIMEHeader{}
+		hdr.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.name+`"`)
+		hdr.Set("Content-Type", f.contentType)
+		part, err := mpWriter.CreatePart(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(part, f.content)
+	}
+	mpWriter.WriteField("note", "hello")
+	mpWriter.WriteField("token", New().FormToken())
+	mpWriter.Close()
+
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Add("Content-Type", mpWriter.FormDataContentType())
+	return req
+}
+
+func TestDecodeFileUploads(t *testing.T) {
+
+	png := testFile{"photo", "me.png", "image/png", "png-bytes"}
+	txt := testFile{"docs", "a.txt", "text/plain", "text a"}
+	csv := testFile{"docs", "b.csv", "text/csv", "x,y"}
+
+	tests := []struct {
+		files     []testFile
+		wantPhoto string
+		wantDocs  int
+		wantErrs  map[string]string
+	}{
+		{[]testFile{png, txt, csv}, "me.png (9 bytes)", 2, nil},
+		{[]testFile{txt}, "", 1, nil},
+		{[]testFile{{"docs", "", "application/octet-stream", ""}}, "", 0, nil}, // no file chosen
+		{[]testFile{{"photo", "big.png", "image/png", strings.Repeat("x", 17)}}, "", 0,
+			map[string]string{"photo": "file is too large - at most 16"}},
+		{[]testFile{{"photo", "me.pdf", "application/pdf", "pdf"}, txt}, "", 1,
+			map[string]string{"photo": "file type is not accepted - expected image/*"}},
+		{[]testFile{txt, {"docs", "c.exe", "application/octet-stream", "MZ"}}, "", 0,
+			map[string]string{"docs": "file type is not accepted - expected .txt,.CSV"}},
+	}
+
+	for idx, tt := range tests {
+		frm := uploadsFormT{}
+		populated, err := New().DecodeMultipartForm(multipartRequest(t, tt.files...), &frm)
+		if !populated || frm.Note != "hello" {
+			t.Errorf("idx%2v: populated %v - note %q", idx, populated, frm.Note)
+		}
+		if frm.Photo.String() != tt.wantPhoto {
+			t.Errorf("idx%2v: photo %q - want %q", idx, frm.Photo, tt.wantPhoto)
+		}
+		if len(frm.Docs) != tt.wantDocs {
+			t.Errorf("idx%2v: %v docs - want %v", idx, len(frm.Docs), tt.wantDocs)
+		}
+		if tt.wantErrs == nil {
+			if err != nil {
+				t.Errorf("idx%2v: unexpected error %v", idx, err)
+			}
+			continue
+		}
+		var des DecodeErrors
+		if !errors.As(err, &des) {
+			t.Errorf("idx%2v: want DecodeErrors - got %v", idx, err)
+			continue
+		}
+		for field, msg := range tt.wantErrs {
+			if got := des.Messages()[field]; got != msg {
+				t.Errorf("idx%2v: %v: got %q - want %q", idx, field, got, msg)
+			}
+		}
+	}
+}
+
+func TestFileUploadOpen(t *testing.T) {
+
+	s2f := New()
+	s2f.MaxMemory = 1 // content goes into a temp file
+	frm := uploadsFormT{}
+	req := multipartRequest(t, testFile{"docs", "a.txt", "text/plain", "text a"})
+	if _, err := s2f.DecodeMultipartForm(req, &frm); err != nil {
+		t.Fatal(err)
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	rdr, err := frm.Docs[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+	bts, _ := io.ReadAll(rdr)
+	if string(bts) != "text a" || frm.Docs[0].ContentType != "text/plain" {
+		t.Errorf("content %q - content type %q", bts, frm.Docs[0].ContentType)
+	}
+
+	if _, err := (FileUpload{}).Open(); !errors.Is(err, ErrNoFile) {
+		t.Errorf("zero FileUpload: %v", err)
+	}
+
+	s2f.MaxUploadSize = 64
+	req = multipartRequest(t, testFile{"docs", "a.txt", "text/plain", strings.Repeat("x", 100)})
+	var tooLarge *http.MaxBytesError
+	if _, err := s2f.DecodeMultipartForm(req, &frm); !errors.As(err, &tooLarge) {
+		t.Errorf("MaxUploadSize: got %v", err)
+	}
+}
+
+func TestFileUploadForm(t *testing.T) {
+
+	s2f := New()
+	if err := s2f.Check(uploadsFormT{}); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	html := string(s2f.Form(uploadsFormT{}))
+	for _, want := range []string{
+		"enctype='multipart/form-data'",
+		"name='docs'     id='docs'     value='ignored.json'  multiple accept='.txt,.CSV'",
+		"name='photo'     id='photo'     value='ignored.json'  accept='image/*' />",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q\n%v", want, html)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"1024", 1024, true},
+		{"512K", 512 << 10, true},
+		{"2MB", 2 << 20, true},
+		{"1g", 1 << 30, true},
+		{"lots", 0, false},
+		{"-1", 0, false},
+	}
+	for idx, tt := range tests {
+		got, err := parseSize(tt.in)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("idx%2v: %q: got %v, %v - want %v", idx, tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestHandlerUploadTooLarge(t *testing.T) {
+
+	s2f := New()
+	s2f.MaxUploadSize = 64
+	h := NewHandler[uploadsFormT](s2f, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, multipartRequest(t, testFile{"docs", "a.txt", "text/plain", strings.Repeat("x", 100)}))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %v - want %v", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+type bytesUploadFormT struct {
+	Note       string `json:"note"`
+	Upload     []byte `json:"upload"       form:"accept='.txt',maxsize='1K',filename='upload_name'"`
+	UploadName string `json:"upload_name"  form:"-"`
+}
+
+func TestDecodeBytesUpload(t *testing.T) {
+
+	tests := []struct {
+		files    []testFile
+		want     string
+		wantName string
+		wantErr  string
+	}{
+		{[]testFile{{"upload", "a.txt", "text/plain", "text a"}}, "text a", "a.txt", ""},
+		{[]testFile{{"upload", "", "application/octet-stream", ""}}, "", "", ""}, // no file chosen
+		{[]testFile{{"upload", "a.exe", "application/octet-stream", "MZ"}}, "", "", "file type is not accepted - expected .txt"},
+		{[]testFile{{"upload", "b.txt", "text/plain", strings.Repeat("x", 1025)}}, "", "", "file is too large - at most 1K"},
+	}
+
+	for idx, tt := range tests {
+		frm := bytesUploadFormT{UploadName: "stale.txt"}
+		_, err := New().DecodeMultipartForm(multipartRequest(t, tt.files...), &frm)
+		if string(frm.Upload) != tt.want || frm.UploadName != tt.wantName {
+			t.Errorf("idx%2v: got %q - %q; want %q - %q", idx, frm.Upload, frm.UploadName, tt.want, tt.wantName)
+		}
+		var des DecodeErrors
+		gotErr := ""
+		if errors.As(err, &des) {
+			gotErr = des.Messages()["upload"]
+		}
+		if gotErr != tt.wantErr {
+			t.Errorf("idx%2v: error %q - want %q", idx, gotErr, tt.wantErr)
+		}
+	}
+}
+
+func TestUploadNameRerendered(t *testing.T) {
+
+	tests := []struct {
+		frm  interface{}
+		want string
+	}{
+		{bytesUploadFormT{UploadName: "<a>.txt"}, "<span class='s2f-upload-name' id='upload_file' >previously: &lt;a&gt;.txt - please select again</span>"},
+		{uploadsFormT{Docs: []FileUpload{{Filename: "a.txt"}, {Filename: "b.csv"}}}, "<span class='s2f-upload-name' id='docs_file' >previously: a.txt, b.csv - please select again</span>"},
+		{uploadsFormT{Photo: FileUpload{Filename: "me.png"}}, "<span class='s2f-upload-name' id='photo_file' >previously: me.png - please select again</span>"},
+		{uploadsFormT{}, ""},
+	}
+	for idx, tt := range tests {
+		html := string(New().Form(tt.frm))
+		if tt.want == "" {
+			if strings.Contains(html, "s2f-upload-name' id=") {
+				t.Errorf("idx%2v: unexpected upload name", idx)
+			}
+			continue
+		}
+		if !strings.Contains(html, tt.want) {
+			t.Errorf("idx%2v: missing %q", idx, tt.want)
+		}
+	}
+}
+
+func TestCheckFilename(t *testing.T) {
+	type badFormT struct {
+		Upload []byte `json:"upload"  form:"filename='upload_name'"`
+	}
+	if err := New().Check(bytesUploadFormT{}); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	err := New().Check(badFormT{})
+	if err == nil || !strings.Contains(err.Error(), "filename refers to unknown string field 'upload_name'") {
+		t.Errorf("Check: got %v", err)
+	}
+}
+
+func TestUploadResubmit(t *testing.T) {
+
+	s2f := New()
+	frm := bytesUploadFormT{}
+	if _, err := s2f.DecodeMultipartForm(multipartRequest(t, testFile{"upload", "a.txt", "text/plain", "text a"}), &frm); err != nil {
+		t.Fatal(err)
+	}
+	s2f.AddError("note", "Please enter a longer note") // validation failed - form rendered again
+	html := string(s2f.Form(frm))
+	if !strings.Contains(html, ">previously: a.txt - please select again</span>") {
+		t.Errorf("previous upload not shown\n%v", html)
+	}
+
+	// browsers do not resend the file - the file input is posted empty
+	s2f = New()
+	if _, err := s2f.DecodeMultipartForm(multipartRequest(t, testFile{"upload", "", "application/octet-stream", ""}), &frm); err != nil {
+		t.Fatal(err)
+	}
+	if len(frm.Upload) != 0 || frm.UploadName != "" {
+		t.Errorf("resubmit without file: got %q - %q", frm.Upload, frm.UploadName)
+	}
+	if html := string(s2f.Form(frm)); strings.Contains(html, "previously:") {
+		t.Errorf("no previous upload after resubmit without file")
+	}
+}
@@ -0,0 +1,98 @@
+// Package vet provides a go/analysis analyzer
+// reporting mistakes in the 'form' struct tags of struc2frm at build time;
+// run it via cmd/struc2frm-vet.
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pbberlin/struc2frm"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer checks structs having 'form' struct tags;
+// the rules are shared with the runtime check s2f.Check()
+var Analyzer = &analysis.Analyzer{
+	Name:     "struc2frm",
+	Doc:      "check 'form' struct tags of struc2frm: syntax, unknown keys, subtypes, json names",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+
+		st := n.(*ast.StructType)
+		if !hasFormTag(st) {
+			return
+		}
+
+		for _, fld := range st.Fields.List {
+			tag := structTag(fld)
+			tp := typeName(pass.TypesInfo.TypeOf(fld.Type))
+			for _, name := range fld.Names {
+				if !name.IsExported() {
+					continue
+				}
+				for _, err := range struc2frm.CheckField(tp, tag.Get("json"), tag.Get("form")) {
+					pos := fld.Pos()
+					if fld.Tag != nil {
+						pos = fld.Tag.Pos()
+					}
+					pass.Reportf(pos, "field %v: %v", name.Name, err)
+				}
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// hasFormTag is true, if any field of the struct has a 'form' tag
+func hasFormTag(st *ast.StructType) bool {
+	for _, fld := range st.Fields.List {
+		if _, ok := structTag(fld).Lookup("form"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func structTag(fld *ast.Field) reflect.StructTag {
+	if fld.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(fld.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// typeName mimics reflect.Type.Name() as used by struc2frm;
+// i.e. string, []string, []uint8
+func typeName(t types.Type) string {
+	switch tt := t.(type) {
+	case nil:
+		return ""
+	case *types.Slice:
+		return "[]" + typeName(tt.Elem())
+	case *types.Basic:
+		return types.Typ[tt.Kind()].Name() // byte => uint8
+	case *types.Named:
+		if b, ok := tt.Underlying().(*types.Basic); ok && tt.Obj().Pkg() == nil {
+			return types.Typ[b.Kind()].Name()
+		}
+		return tt.Obj().Name()
+	}
+	return strings.TrimPrefix(types.TypeString(t, nil), "*")
+}
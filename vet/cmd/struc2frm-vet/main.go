@@ -0,0 +1,17 @@
+// Command struc2frm-vet reports mistakes in 'form' struct tags of struc2frm;
+// installed from a checkout of the repository - its module replaces the library by ../
+//
+//	cd struc2frm/vet && go install ./cmd/struc2frm-vet
+//	struc2frm-vet ./...
+//
+// or via go vet -vettool=$(which struc2frm-vet) ./...
+package main
+
+import (
+	"github.com/pbberlin/struc2frm/vet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(vet.Analyzer)
+}
@@ -0,0 +1,17 @@
+module github.com/pbberlin/struc2frm/vet
+
+go 1.22.0
+
+require (
+	github.com/pbberlin/struc2frm v0.0.0
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/go-playground/form v3.1.4+incompatible // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
+
+// the analyzer shares the rules of the library in this repository
+replace github.com/pbberlin/struc2frm => ../
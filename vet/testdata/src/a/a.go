@@ -0,0 +1,19 @@
+package a
+
+type entryForm struct {
+	Department string   `json:"department"  form:"subtype='select',onchange='true'"`
+	Groups     int      `json:"groups"      form:"min=1,max='100',maxlenght='3'"`      // want `field Groups: tag 'form': unknown key 'maxlenght'`
+	Items      []string `json:"items"       form:"subtype='textarea',multiple='true'"` // want `field Items: tag 'form': subtype='textarea' is not supported for type \[\]string`
+	Check      bool     `form:"label='Check'"`                                         // want `field Check: missing json tag - required as input name`
+	Pattern    string   `json:"pattern"     form:"pattern='[0-9]{2,10}"`               // want `field Pattern: tag 'form' at position 8: unterminated quote for key 'pattern'`
+	Count      int      `json:"count"       form:"multiple='true'"`                    // want `field Count: tag 'form': multiple requires a slice type - not int`
+	Upload     []byte   `json:"upload"      form:"accept='.txt'"`
+	Hidden     string   `json:"hidden"      form:"-"`
+	unexported string
+}
+
+// no form tags - not checked
+type other struct {
+	Name string `json:"name"`
+	Age  int
+}
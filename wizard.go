@@ -0,0 +1,319 @@
+package struc2frm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// wizardStateName is the name of the hidden input carrying the signed wizard state
+const wizardStateName = "wizard_s2f"
+
+// wizardBtnName is the name of the back and next buttons of a wizard
+const wizardBtnName = "btnWizard"
+
+// Wizard is a form spanning several steps - one struct per step;
+// the values of all steps travel in a signed hidden input -
+// no server side session is required.
+// The state is signed with s2f.Salt - which defaults to the MAC address;
+// set Salt to a random secret before trusting the state.
+// Create one per request:
+//
+//	wz := s2f.NewWizard(&contactT{}, &addressT{}, &paymentT{})
+//	done, err := wz.Decode(r)
+//	if done {
+//		// wz.Steps hold the validated structs
+//	}
+//	fmt.Fprint(w, wz.Form())
+type Wizard struct {
+	Steps   []interface{} // pointers to the step structs
+	Encrypt bool          // encrypt the state - keyed by s2f.Salt; otherwise it is only signed
+
+	step int
+	s2f  *s2FT
+}
+
+// wizardState is carried from step to step
+type wizardState struct {
+	Step   int               `json:"step"`
+	Issued int64             `json:"issued"` // unix seconds; expires like the form token
+	Values []json.RawMessage `json:"values"` // one per step
+}
+
+// wizardNav is set on the converter while rendering a step;
+// it replaces the submit button by back and next buttons
+type wizardNav struct {
+	state       string
+	step, steps int
+}
+
+// NewWizard creates a wizard for the steps - pointers to structs;
+// the steps are rendered by a clone of s2f - see CloneForRequest().
+func (s2f *s2FT) NewWizard(steps ...interface{}) *Wizard {
+	return &Wizard{Steps: steps, s2f: s2f.CloneForRequest()}
+}
+
+// Step returns the index of the current step
+func (wz *Wizard) Step() int {
+	return wz.step
+}
+
+// Converter returns the converter rendering the steps;
+// i.e. for AddError()
+func (wz *Wizard) Converter() *s2FT {
+	return wz.s2f
+}
+
+// stepValue returns the struct of step idx
+func (wz *Wizard) stepValue(idx int) (reflect.Value, error) {
+	v := reflect.ValueOf(wz.Steps[idx])
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return v, fmt.Errorf("%w - step %v must be pointer to struct - is %v", ErrNotStruct, idx, v.Kind())
+	}
+	return v.Elem(), nil
+}
+
+// Decode restores all steps from the wizard state of request r
+// and decodes the current step from the request values;
+// the button 'next' validates the current step - s2f.Validate() -
+// and moves on, if it is valid; 'back' returns without validation.
+// done is true, if the last step was submitted valid.
+// Decode and validation errors are added to the converter;
+// err reports invalid requests - such as ErrWizardState or an invalid form token.
+func (wz *Wizard) Decode(r *http.Request) (done bool, err error) {
+
+	if len(wz.Steps) == 0 {
+		return false, fmt.Errorf("%w - wizard has no steps", ErrNotStruct)
+	}
+	for idx := range wz.Steps {
+		if _, err := wz.stepValue(idx); err != nil {
+			return false, err
+		}
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return false, fmt.Errorf("struc2frm: cannot parse form: %w", err)
+	}
+	if r.Form.Get(wizardStateName) == "" && r.Form.Get("token") == "" {
+		return false, nil // first request
+	}
+
+	st, err := wz.openState(r.Form.Get(wizardStateName))
+	if err != nil {
+		wz.s2f.AddDecodeErrors(err)
+		return false, err
+	}
+	for idx, raw := range st.Values {
+		if idx < len(wz.Steps) && len(raw) > 0 {
+			if err := json.Unmarshal(raw, wz.Steps[idx]); err != nil {
+				wz.s2f.AddDecodeErrors(ErrWizardState)
+				return false, fmt.Errorf("%w: step %v: %v", ErrWizardState, idx, err)
+			}
+		}
+	}
+	wz.step = st.Step
+
+	// the current step is posted completely - reset it to prevent accumulation of slice values
+	cur, _ := wz.stepValue(wz.step)
+	sch := schemaOf(cur.Type())
+	for _, fs := range sch.fields {
+		if fs.exported && !fs.skip {
+			cur.Field(fs.index).Set(reflect.Zero(cur.Field(fs.index).Type()))
+		}
+	}
+	_, decErr := wz.s2f.Decode(r, wz.Steps[wz.step])
+	var des DecodeErrors
+	if decErr != nil && !errors.As(decErr, &des) {
+		wz.s2f.AddDecodeErrors(decErr) // token or bot guard
+		return false, decErr
+	}
+
+	if r.Form.Get(wizardBtnName) == "back" {
+		if wz.step > 0 {
+			wz.step-- // decode errors are shown, when the step is revisited
+		}
+		return false, nil
+	}
+
+	if decErr != nil {
+		wz.s2f.AddDecodeErrors(decErr)
+		return false, decErr
+	}
+	errs, valid := wz.s2f.Validate(wz.Steps[wz.step])
+	if !valid {
+		wz.s2f.AddErrors(errs)
+		return false, nil
+	}
+	if wz.step == len(wz.Steps)-1 {
+		return true, nil
+	}
+	wz.step++
+	return false, nil
+}
+
+// Form renders the current step;
+// errors are rendered as text - use RenderForm() to handle them.
+func (wz *Wizard) Form() template.HTML {
+	w := &bytes.Buffer{}
+	if err := wz.RenderForm(w); err != nil {
+		return template.HTML(template.HTMLEscapeString(fmt.Sprintf("struct2form.Wizard.Form() - %v", err)))
+	}
+	return template.HTML(w.String())
+}
+
+// RenderForm writes the current step to wr -
+// with the wizard state and back and next buttons;
+// errors as for s2f.RenderForm().
+func (wz *Wizard) RenderForm(wr io.Writer) error {
+	if len(wz.Steps) == 0 {
+		return fmt.Errorf("%w - wizard has no steps", ErrNotStruct)
+	}
+	cur, err := wz.stepValue(wz.step)
+	if err != nil {
+		return err
+	}
+	state, err := wz.sealState()
+	if err != nil {
+		return err
+	}
+	wz.s2f.wizard = &wizardNav{state: state, step: wz.step, steps: len(wz.Steps)}
+	defer func() { wz.s2f.wizard = nil }()
+	return wz.s2f.RenderForm(wr, cur.Interface())
+}
+
+// sealState encodes the current step and the values of all steps;
+// base64 - encrypted if wz.Encrypt - dot signature
+func (wz *Wizard) sealState() (string, error) {
+
+	st := wizardState{Step: wz.step, Issued: time.Now().Unix()}
+	for _, step := range wz.Steps {
+		raw, err := json.Marshal(step)
+		if err != nil {
+			return "", fmt.Errorf("struc2frm: wizard state: %w", err)
+		}
+		st.Values = append(st.Values, raw)
+	}
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return "", fmt.Errorf("struc2frm: wizard state: %w", err)
+	}
+
+	if wz.Encrypt {
+		gcm, err := wz.s2f.stateCipher()
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("struc2frm: wizard state: %w", err)
+		}
+		payload = gcm.Seal(nonce, nonce, payload, nil)
+	}
+
+	enc := base64.RawURLEncoding.EncodeToString(payload)
+	return enc + "." + wz.stateMAC(enc), nil
+}
+
+// stateMAC signs enc - prefixed by purpose and the step types;
+// neither flash cookies nor the states of other wizards are accepted
+func (wz *Wizard) stateMAC(enc string) string {
+	names := make([]string, 0, len(wz.Steps))
+	for _, step := range wz.Steps {
+		tp := reflect.TypeOf(step)
+		for tp != nil && tp.Kind() == reflect.Ptr {
+			tp = tp.Elem()
+		}
+		if tp == nil {
+			names = append(names, "nil")
+			continue
+		}
+		names = append(names, tp.PkgPath()+"."+tp.Name())
+	}
+	return wz.s2f.sign("wizard:" + strings.Join(names, ",") + ":" + enc)
+}
+
+// openState verifies and decodes the value of sealState();
+// returns ErrWizardState or ErrTokenExpired
+func (wz *Wizard) openState(s string) (wizardState, error) {
+
+	st := wizardState{}
+	enc, sig, ok := strings.Cut(s, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(wz.stateMAC(enc))) {
+		return st, ErrWizardState // missing, forged or from another wizard
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(enc)
+	if err != nil {
+		return st, ErrWizardState
+	}
+
+	if wz.Encrypt {
+		gcm, err := wz.s2f.stateCipher()
+		if err != nil {
+			return st, err
+		}
+		if len(payload) < gcm.NonceSize() {
+			return st, ErrWizardState
+		}
+		payload, err = gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], nil)
+		if err != nil {
+			return st, ErrWizardState
+		}
+	}
+
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return st, ErrWizardState
+	}
+	if st.Step < 0 || st.Step >= len(wz.Steps) {
+		return st, ErrWizardState
+	}
+	maxAge := time.Duration(wz.s2f.FormTimeout+1) * time.Hour
+	if time.Since(time.Unix(st.Issued, 0)) > maxAge {
+		return st, ErrTokenExpired
+	}
+	return st, nil
+}
+
+// stateCipher returns AES-GCM keyed by a hash of s2f.Salt
+func (s2f *s2FT) stateCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("wizard-state:" + s2f.Salt))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("struc2frm: wizard state: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// renderState writes the hidden state input and the progress of the wizard
+func (wn *wizardNav) renderState(w io.Writer) {
+	fmt.Fprintf(w, "\t<input name='%v' type='hidden'   value='%v' />\n", wizardStateName, wn.state)
+	fmt.Fprintf(w, "\t<p class='s2f-wizard-progress' >Step %v of %v</p>\n", wn.step+1, wn.steps)
+}
+
+// renderButtons writes next - or finish - and back;
+// next comes first, so that the enter key moves forward
+func (wn *wizardNav) renderButtons(w io.Writer, spacer string) {
+	label := "<b>N</b>ext"
+	accesskey := "n"
+	if wn.step == wn.steps-1 {
+		label = "<b>F</b>inish"
+		accesskey = "f"
+	}
+	fmt.Fprintf(w, "\t<button  type='submit' name='%v' value='next' accesskey='%v'  >%v</button>\n", wizardBtnName, accesskey, label)
+	if wn.step > 0 {
+		fmt.Fprintf(w, "\t<button  type='submit' name='%v' value='back' accesskey='b' class='s2f-wizard-back' formnovalidate >%v</button>\n", wizardBtnName, "<b>B</b>ack")
+	}
+	fmt.Fprintf(w, "%v\n", spacer)
+}
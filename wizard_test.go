@@ -0,0 +1,185 @@
+package struc2frm
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type wizContactT struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (frm wizContactT) Validate() (map[string]string, bool) {
+	errs := map[string]string{}
+	if frm.Name == "" {
+		errs["name"] = "Please enter your name"
+	}
+	return errs, len(errs) == 0
+}
+
+type wizAddressT struct {
+	City string `json:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type wizConfirmT struct {
+	Accepted bool `json:"accepted"`
+}
+
+var wizState = regexp.MustCompile(`name='wizard_s2f' type='hidden'   value='([^']+)'`)
+
+// wizardPost submits the rendered step html with vals and button btn
+func wizardPost(t *testing.T, s2f *s2FT, html string, vals map[string]string, btn string) *http.Request {
+	t.Helper()
+	data := url.Values{}
+	data.Set("token", s2f.FormToken())
+	if m := wizState.FindStringSubmatch(html); m != nil {
+		data.Set("wizard_s2f", m[1])
+	}
+	for key, val := range vals {
+		data.Set(key, val)
+	}
+	data.Set("btnWizard", btn)
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestWizard(t *testing.T) {
+
+	for _, encrypt := range []bool{false, true} {
+
+		s2f := New()
+		html := ""
+
+		steps := []struct {
+			vals     map[string]string
+			btn      string
+			wantStep int
+			wantDone bool
+			want     string
+		}{
+			{nil, "", 0, false, "Step 1 of 3"},
+			{map[string]string{"name": "", "email": "j@x.org"}, "next", 0, false, "Please enter your name"},
+			{map[string]string{"name": "Jane", "email": "j@x.org"}, "next", 1, false, "Step 2 of 3"},
+			{map[string]string{"city": "Berlin", "zip": "abc"}, "next", 1, false, "must be a number"},
+			{map[string]string{"city": "Berlin", "zip": "10115"}, "back", 0, false, "value='Jane'"},
+			{map[string]string{"name": "Jane", "email": "j@x.org"}, "next", 1, false, "value='10115'"},
+			{map[string]string{"city": "Berlin", "zip": "10115"}, "next", 2, false, "<b>F</b>inish"},
+			{map[string]string{"accepted": "true"}, "next", 2, true, ""},
+		}
+
+		for idx, st := range steps {
+
+			contact, address, confirm := &wizContactT{}, &wizAddressT{}, &wizConfirmT{}
+			wz := s2f.NewWizard(contact, address, confirm)
+			wz.Encrypt = encrypt
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			if idx > 0 {
+				req = wizardPost(t, s2f, html, st.vals, st.btn)
+			}
+			done, err := wz.Decode(req)
+			var des DecodeErrors
+			if err != nil && !errors.As(err, &des) {
+				t.Fatalf("encrypt %v idx%2v: Decode() %v", encrypt, idx, err)
+			}
+			if done != st.wantDone || wz.Step() != st.wantStep {
+				t.Errorf("encrypt %v idx%2v: done %v step %v - want %v %v", encrypt, idx, done, wz.Step(), st.wantDone, st.wantStep)
+			}
+			if done {
+				if contact.Name != "Jane" || address.Zip != 10115 || !confirm.Accepted {
+					t.Errorf("encrypt %v idx%2v: completed steps %+v %+v %+v", encrypt, idx, contact, address, confirm)
+				}
+				continue
+			}
+
+			html = string(wz.Form())
+			if !strings.Contains(html, st.want) {
+				t.Errorf("encrypt %v idx%2v: missing %q", encrypt, idx, st.want)
+			}
+			if hasBack := strings.Contains(html, "value='back'"); hasBack != (st.wantStep > 0) {
+				t.Errorf("encrypt %v idx%2v: back button %v", encrypt, idx, hasBack)
+			}
+			if strings.Contains(html, "name='btnSubmit'") {
+				t.Errorf("encrypt %v idx%2v: submit button should be replaced", encrypt, idx)
+			}
+
+			m := wizState.FindStringSubmatch(html)
+			if m == nil {
+				t.Fatalf("encrypt %v idx%2v: wizard state missing", encrypt, idx)
+			}
+			payload, _ := base64.RawURLEncoding.DecodeString(strings.Split(m[1], ".")[0])
+			if plain := strings.Contains(string(payload), `"name":`); plain == encrypt {
+				t.Errorf("encrypt %v idx%2v: state is readable %v", encrypt, idx, plain)
+			}
+		}
+	}
+}
+
+func TestWizardState(t *testing.T) {
+
+	s2f := New()
+	wz := s2f.NewWizard(&wizContactT{}, &wizAddressT{})
+	html := string(wz.Form())
+	state := wizState.FindStringSubmatch(html)[1]
+
+	tests := []struct {
+		state string
+		want  error
+	}{
+		{"", ErrWizardState},
+		{"e30." + strings.Repeat("0", 64), ErrWizardState},
+		{strings.Replace(state, ".", "x.", 1), ErrWizardState},
+	}
+	for idx, tt := range tests {
+		data := url.Values{}
+		data.Set("token", s2f.FormToken())
+		data.Set("wizard_s2f", tt.state)
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		wz := s2f.NewWizard(&wizContactT{}, &wizAddressT{})
+		if _, err := wz.Decode(req); !errors.Is(err, tt.want) {
+			t.Errorf("idx%2v: got %v - want %v", idx, err, tt.want)
+		}
+	}
+
+	// encrypted state is not accepted unencrypted
+	data := url.Values{}
+	data.Set("token", s2f.FormToken())
+	data.Set("wizard_s2f", state)
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	wz = s2f.NewWizard(&wizContactT{}, &wizAddressT{})
+	wz.Encrypt = true
+	if _, err := wz.Decode(req); !errors.Is(err, ErrWizardState) {
+		t.Errorf("plain state decoded as encrypted: %v", err)
+	}
+
+	// states of other wizards and flash cookies are not accepted
+	rec := httptest.NewRecorder()
+	s2f.SetFlash(rec, httptest.NewRequest("POST", "/", nil), fmt.Sprintf(`{"step":0,"issued":%v,"values":[{},{}]}`, time.Now().Unix()))
+	for idx, foreign := range []string{state, rec.Result().Cookies()[0].Value} {
+		data.Set("wizard_s2f", foreign)
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(data.Encode()))
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		wz = s2f.NewWizard(&wizAddressT{}, &wizContactT{})
+		if _, err := wz.Decode(req); !errors.Is(err, ErrWizardState) {
+			t.Errorf("idx%2v: foreign state accepted: %v", idx, err)
+		}
+	}
+
+	if _, err := s2f.NewWizard(wizContactT{}).Decode(req); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("steps must be pointers - got %v", err)
+	}
+}